@@ -3,7 +3,6 @@ package splash
 import (
 	"context"
 	"fmt"
-	"log"
 	"sort"
 
 	"github.com/onflow/flow-go-sdk"
@@ -11,10 +10,12 @@ import (
 	"github.com/onflow/flowkit/v2/accounts"
 )
 
+// CreateAccounts ensures that all accounts present in the deployment block for the given network is present.
+// It panics if any of them can't be created.
 func (c *Connector) CreateAccounts(ctx context.Context, saAccountName string) *Connector {
 	conn, err := c.CreateAccountsE(ctx, saAccountName)
 	if err != nil {
-		log.Fatal(err)
+		panic(err)
 	}
 
 	return conn
@@ -69,9 +70,10 @@ func (c *Connector) CreateAccountsE(ctx context.Context, saAccountName string) (
 }
 
 // InitializeContracts installs all contracts in the deployment block for the configured network
+// and panics if it fails.
 func (c *Connector) InitializeContracts(ctx context.Context) *Connector {
 	if err := c.InitializeContractsE(ctx); err != nil {
-		log.Fatal(err)
+		panic(err)
 	}
 
 	return c
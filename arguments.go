@@ -0,0 +1,686 @@
+package splash
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/parser"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// parameter is a parameter of a transaction or a script's main function
+type parameter struct {
+	Name string
+	Type ast.Type
+}
+
+// parseParameters returns the parameters of the transaction or the main function of the script
+func parseParameters(code []byte) ([]parameter, error) {
+	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Cadence code: %w", err)
+	}
+
+	var parameterList *ast.ParameterList
+	if tx := program.SoleTransactionDeclaration(); tx != nil {
+		parameterList = tx.ParameterList
+	} else {
+		for _, function := range program.FunctionDeclarations() {
+			if function.Identifier.Identifier == "main" {
+				parameterList = function.ParameterList
+				break
+			}
+		}
+		if parameterList == nil {
+			return nil, errors.New("the code has neither a transaction nor a main function")
+		}
+	}
+
+	if parameterList == nil {
+		return nil, nil
+	}
+
+	parameters := make([]parameter, len(parameterList.Parameters))
+	for i, p := range parameterList.Parameters {
+		parameters[i] = parameter{
+			Name: p.Identifier.Identifier,
+			Type: p.TypeAnnotation.Type,
+		}
+	}
+
+	return parameters, nil
+}
+
+// convertArguments converts Go values to Cadence values using the types of the parameters,
+// starting from the parameter at the given offset
+func convertArguments(code []byte, offset int, values []any) ([]cadence.Value, error) {
+	parameters, err := parseParameters(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset+len(values) > len(parameters) {
+		return nil, fmt.Errorf("%w: got %d, the code takes %d", ErrWrongArgumentCount, offset+len(values), len(parameters))
+	}
+
+	result := make([]cadence.Value, len(values))
+	for i, value := range values {
+		p := parameters[offset+i]
+		result[i], err = ToCadenceValue(value, p.Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %w", p.Name, err)
+		}
+	}
+
+	return result, nil
+}
+
+// ToCadenceValue converts a Go value to a Cadence value of the given type. Cadence values are passed
+// through as they are. Pointers are dereferenced, a nil pointer becomes an empty optional.
+func ToCadenceValue(value any, cadenceType ast.Type) (cadence.Value, error) {
+	if v, ok := value.(cadence.Value); ok {
+		return v, nil
+	}
+
+	rv := reflect.ValueOf(value)
+
+	if optionalType, ok := cadenceType.(*ast.OptionalType); ok {
+		if value == nil || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+			return cadence.NewOptional(nil), nil
+		}
+		if rv.Kind() == reflect.Pointer {
+			return ToCadenceValue(rv.Elem().Interface(), cadenceType)
+		}
+		inner, err := ToCadenceValue(value, optionalType.Type)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptional(inner), nil
+	}
+
+	if value == nil {
+		return nil, fmt.Errorf("nil is not a valid %s", cadenceType)
+	}
+	if rv.Kind() == reflect.Pointer && rv.Type() != bigIntType {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("nil is not a valid %s", cadenceType)
+		}
+		return ToCadenceValue(rv.Elem().Interface(), cadenceType)
+	}
+
+	switch t := cadenceType.(type) {
+	case *ast.VariableSizedType:
+		return toCadenceArray(rv, t.Type, -1, cadenceType)
+	case *ast.ConstantSizedType:
+		return toCadenceArray(rv, t.Type, int(t.Size.Value.Int64()), cadenceType)
+	case *ast.DictionaryType:
+		return toCadenceDictionary(rv, t.KeyType, t.ValueType, cadenceType)
+	case *ast.NominalType:
+		if len(t.NestedIdentifiers) == 0 {
+			return toCadenceSimpleValue(value, t.Identifier.Identifier)
+		}
+	}
+
+	return nil, fmt.Errorf("can't convert %T to %s, pass a cadence.Value instead", value, cadenceType)
+}
+
+var bigIntType = reflect.TypeOf(&big.Int{})
+
+func toCadenceArray(rv reflect.Value, elementType ast.Type, size int, cadenceType ast.Type) (cadence.Value, error) {
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("can't convert %s to %s", rv.Type(), cadenceType)
+	}
+	if size >= 0 && rv.Len() != size {
+		return nil, fmt.Errorf("%s needs %d elements, got %d", cadenceType, size, rv.Len())
+	}
+
+	values := make([]cadence.Value, rv.Len())
+	for i := range values {
+		v, err := ToCadenceValue(rv.Index(i).Interface(), elementType)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		values[i] = v
+	}
+
+	return cadence.NewArray(values), nil
+}
+
+func toCadenceDictionary(rv reflect.Value, keyType, valueType ast.Type, cadenceType ast.Type) (cadence.Value, error) {
+	if rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("can't convert %s to %s", rv.Type(), cadenceType)
+	}
+
+	pairs := make([]cadence.KeyValuePair, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key, err := ToCadenceValue(iter.Key().Interface(), keyType)
+		if err != nil {
+			return nil, fmt.Errorf("key %v: %w", iter.Key(), err)
+		}
+		value, err := ToCadenceValue(iter.Value().Interface(), valueType)
+		if err != nil {
+			return nil, fmt.Errorf("value of %v: %w", iter.Key(), err)
+		}
+		pairs = append(pairs, cadence.KeyValuePair{Key: key, Value: value})
+	}
+
+	// Go maps have no order, sort the pairs so that the encoded arguments are deterministic
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Key.String() < pairs[j].Key.String()
+	})
+
+	return cadence.NewDictionary(pairs), nil
+}
+
+// integerTypes holds the range of the bounded Cadence integer types
+var integerTypes = map[string]struct {
+	bits   uint
+	signed bool
+}{
+	"Int8": {8, true}, "Int16": {16, true}, "Int32": {32, true}, "Int64": {64, true},
+	"Int128": {128, true}, "Int256": {256, true},
+	"UInt8": {8, false}, "UInt16": {16, false}, "UInt32": {32, false}, "UInt64": {64, false},
+	"UInt128": {128, false}, "UInt256": {256, false},
+	"Word8": {8, false}, "Word16": {16, false}, "Word32": {32, false}, "Word64": {64, false},
+	"Word128": {128, false}, "Word256": {256, false},
+}
+
+func toCadenceSimpleValue(value any, typeName string) (cadence.Value, error) {
+	switch typeName {
+	case "String":
+		switch v := value.(type) {
+		case string:
+			return cadence.String(v), nil
+		case time.Time:
+			return cadence.String(v.Format(time.RFC3339Nano)), nil
+		}
+	case "Character":
+		if s, ok := value.(string); ok {
+			return cadence.NewCharacter(s)
+		}
+	case "Bool":
+		if b, ok := value.(bool); ok {
+			return cadence.NewBool(b), nil
+		}
+	case "Address":
+		switch v := value.(type) {
+		case flow.Address:
+			return cadence.NewAddress(v), nil
+		case string:
+			return cadence.NewAddress(flow.HexToAddress(v)), nil
+		}
+	case "Path", "StoragePath", "PublicPath", "PrivatePath", "CapabilityPath":
+		if s, ok := value.(string); ok {
+			return StringToPath(s)
+		}
+	case "Fix64", "UFix64":
+		return toCadenceFixedPoint(value, typeName)
+	case "Int", "UInt":
+		n, err := toBigInt(value)
+		if err != nil {
+			return nil, fmt.Errorf("can't convert %T to %s: %w", value, typeName, err)
+		}
+		if typeName == "UInt" {
+			return cadence.NewUIntFromBig(n)
+		}
+		return cadence.NewIntFromBig(n), nil
+	case "AnyStruct":
+		return inferCadenceValue(value)
+	default:
+		if _, ok := integerTypes[typeName]; ok {
+			return toCadenceInteger(value, typeName)
+		}
+	}
+
+	return nil, fmt.Errorf("can't convert %T to %s", value, typeName)
+}
+
+func toCadenceInteger(value any, typeName string) (cadence.Value, error) {
+	n, err := toBigInt(value)
+	if err != nil {
+		return nil, fmt.Errorf("can't convert %T to %s: %w", value, typeName, err)
+	}
+
+	limits := integerTypes[typeName]
+	min, max := new(big.Int), new(big.Int).Lsh(big.NewInt(1), limits.bits)
+	if limits.signed {
+		max.Rsh(max, 1)
+		min.Neg(max)
+	}
+	max.Sub(max, big.NewInt(1))
+	if n.Cmp(min) < 0 || n.Cmp(max) > 0 {
+		return nil, fmt.Errorf("%s is out of range for %s", n, typeName)
+	}
+
+	switch typeName {
+	case "Int8":
+		return cadence.NewInt8(int8(n.Int64())), nil
+	case "Int16":
+		return cadence.NewInt16(int16(n.Int64())), nil
+	case "Int32":
+		return cadence.NewInt32(int32(n.Int64())), nil
+	case "Int64":
+		return cadence.NewInt64(n.Int64()), nil
+	case "Int128":
+		return cadence.NewInt128FromBig(n)
+	case "Int256":
+		return cadence.NewInt256FromBig(n)
+	case "UInt8":
+		return cadence.NewUInt8(uint8(n.Uint64())), nil
+	case "UInt16":
+		return cadence.NewUInt16(uint16(n.Uint64())), nil
+	case "UInt32":
+		return cadence.NewUInt32(uint32(n.Uint64())), nil
+	case "UInt64":
+		return cadence.NewUInt64(n.Uint64()), nil
+	case "UInt128":
+		return cadence.NewUInt128FromBig(n)
+	case "UInt256":
+		return cadence.NewUInt256FromBig(n)
+	case "Word8":
+		return cadence.NewWord8(uint8(n.Uint64())), nil
+	case "Word16":
+		return cadence.NewWord16(uint16(n.Uint64())), nil
+	case "Word32":
+		return cadence.NewWord32(uint32(n.Uint64())), nil
+	case "Word64":
+		return cadence.NewWord64(n.Uint64()), nil
+	case "Word128":
+		return cadence.NewWord128FromBig(n)
+	default:
+		return cadence.NewWord256FromBig(n)
+	}
+}
+
+// toBigInt converts Go integers, big integers, decimal strings and times (as Unix seconds) to a big.Int
+func toBigInt(value any) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return new(big.Int).Set(v), nil
+	case big.Int:
+		return new(big.Int).Set(&v), nil
+	case string:
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return nil, fmt.Errorf("%q is not an integer", v)
+		}
+		return n, nil
+	case time.Time:
+		return big.NewInt(v.Unix()), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+
+	return nil, errors.New("not an integer")
+}
+
+func toCadenceFixedPoint(value any, typeName string) (cadence.Value, error) {
+	var s string
+	switch v := value.(type) {
+	case Decimal:
+		if typeName == "UFix64" {
+			return v.UFix64()
+		}
+		return v.Fix64()
+	case string:
+		s = v
+	case float32:
+		s = strconv.FormatFloat(float64(v), 'f', 8, 32)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', 8, 64)
+	case time.Time:
+		timestamp, err := timestampDecimal(v, typeName == "Fix64")
+		if err != nil {
+			return nil, fmt.Errorf("%s is out of range for %s: %w", v.Format(time.RFC3339Nano), typeName, err)
+		}
+		return timestamp.Cadence(), nil
+	default:
+		n, err := toBigInt(value)
+		if err != nil {
+			return nil, fmt.Errorf("can't convert %T to %s", value, typeName)
+		}
+		s = n.String() + ".0"
+	}
+
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	if typeName == "UFix64" {
+		return cadence.NewUFix64(s)
+	}
+	return cadence.NewFix64(s)
+}
+
+// inferCadenceValue converts a Go value without a declared Cadence type, e.g. for AnyStruct parameters
+func inferCadenceValue(value any) (cadence.Value, error) {
+	switch v := value.(type) {
+	case cadence.Value:
+		return v, nil
+	case string:
+		return cadence.String(v), nil
+	case bool:
+		return cadence.NewBool(v), nil
+	case flow.Address:
+		return cadence.NewAddress(v), nil
+	case *big.Int, big.Int:
+		return toCadenceSimpleValue(v, "Int")
+	case Decimal:
+		return v.Cadence(), nil
+	case float32, float64:
+		return toCadenceFixedPoint(v, "Fix64")
+	case time.Time:
+		return toCadenceFixedPoint(v, "UFix64")
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int:
+		return cadence.NewInt(int(rv.Int())), nil
+	case reflect.Int8:
+		return cadence.NewInt8(int8(rv.Int())), nil
+	case reflect.Int16:
+		return cadence.NewInt16(int16(rv.Int())), nil
+	case reflect.Int32:
+		return cadence.NewInt32(int32(rv.Int())), nil
+	case reflect.Int64:
+		return cadence.NewInt64(rv.Int()), nil
+	case reflect.Uint:
+		return cadence.NewUInt(uint(rv.Uint())), nil
+	case reflect.Uint8:
+		return cadence.NewUInt8(uint8(rv.Uint())), nil
+	case reflect.Uint16:
+		return cadence.NewUInt16(uint16(rv.Uint())), nil
+	case reflect.Uint32:
+		return cadence.NewUInt32(uint32(rv.Uint())), nil
+	case reflect.Uint64:
+		return cadence.NewUInt64(rv.Uint()), nil
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return cadence.NewOptional(nil), nil
+		}
+		inner, err := inferCadenceValue(rv.Elem().Interface())
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptional(inner), nil
+	case reflect.Slice, reflect.Array:
+		values := make([]cadence.Value, rv.Len())
+		for i := range values {
+			v, err := inferCadenceValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return cadence.NewArray(values), nil
+	case reflect.Map:
+		pairs := make([]cadence.KeyValuePair, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, err := inferCadenceValue(iter.Key().Interface())
+			if err != nil {
+				return nil, err
+			}
+			val, err := inferCadenceValue(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, cadence.KeyValuePair{Key: key, Value: val})
+		}
+		sort.Slice(pairs, func(i, j int) bool {
+			return pairs[i].Key.String() < pairs[j].Key.String()
+		})
+		return cadence.NewDictionary(pairs), nil
+	}
+
+	return nil, fmt.Errorf("can't infer the Cadence type of %T", value)
+}
+
+// ArgumentError describes an argument that doesn't match the parameter declared by the transaction or script
+type ArgumentError struct {
+	Index     int
+	Parameter string
+	Expected  string
+	Actual    string
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("argument %d (%s): expected %s, got %s", e.Index, e.Parameter, e.Expected, e.Actual)
+}
+
+// validateArguments checks the number and types of the arguments against the parameters declared in the code.
+// Code that can't be parsed locally isn't validated, so that the network reports the actual error.
+func validateArguments(code []byte, args []cadence.Value) error {
+	parameters, err := parseParameters(code)
+	if err != nil {
+		return nil
+	}
+
+	if len(args) != len(parameters) {
+		names := make([]string, len(parameters))
+		for i, p := range parameters {
+			names[i] = fmt.Sprintf("%s: %s", p.Name, p.Type)
+		}
+		return fmt.Errorf("%w: expected %d (%s), got %d", ErrWrongArgumentCount, len(parameters), strings.Join(names, ", "), len(args))
+	}
+
+	for i, p := range parameters {
+		if !valueMatchesType(args[i], p.Type) {
+			return &ArgumentError{
+				Index:     i,
+				Parameter: p.Name,
+				Expected:  p.Type.String(),
+				Actual:    cadenceTypeName(args[i]),
+			}
+		}
+	}
+
+	return nil
+}
+
+// valueMatchesType returns false if the value can't be an instance of the declared type.
+// Types that can't be checked without the type checker (abstract types, references, capabilities, intersections
+// and user defined types) are accepted.
+func valueMatchesType(value cadence.Value, cadenceType ast.Type) bool {
+	if value == nil {
+		return false
+	}
+
+	switch t := cadenceType.(type) {
+	case *ast.OptionalType:
+		if optional, ok := value.(cadence.Optional); ok {
+			return optional.Value == nil || valueMatchesType(optional.Value, t.Type)
+		}
+		return valueMatchesType(value, t.Type)
+
+	case *ast.VariableSizedType:
+		return arrayMatchesType(value, t.Type, -1)
+
+	case *ast.ConstantSizedType:
+		return arrayMatchesType(value, t.Type, t.Size.Value.Int64())
+
+	case *ast.DictionaryType:
+		dictionary, ok := value.(cadence.Dictionary)
+		if !ok {
+			return false
+		}
+		for _, pair := range dictionary.Pairs {
+			if !valueMatchesType(pair.Key, t.KeyType) || !valueMatchesType(pair.Value, t.ValueType) {
+				return false
+			}
+		}
+		return true
+
+	case *ast.NominalType:
+		return valueMatchesNominalType(value, t)
+	}
+
+	return true
+}
+
+func arrayMatchesType(value cadence.Value, elementType ast.Type, size int64) bool {
+	array, ok := value.(cadence.Array)
+	if !ok || (size >= 0 && int64(len(array.Values)) != size) {
+		return false
+	}
+	for _, element := range array.Values {
+		if !valueMatchesType(element, elementType) {
+			return false
+		}
+	}
+	return true
+}
+
+// simpleTypeChecks checks values of the built-in Cadence types that can be declared by name
+var simpleTypeChecks = map[string]func(cadence.Value) bool{
+	"String":    func(v cadence.Value) bool { _, ok := v.(cadence.String); return ok },
+	"Character": func(v cadence.Value) bool { _, ok := v.(cadence.Character); return ok },
+	"Bool":      func(v cadence.Value) bool { _, ok := v.(cadence.Bool); return ok },
+	"Address":   func(v cadence.Value) bool { _, ok := v.(cadence.Address); return ok },
+	"Type":      func(v cadence.Value) bool { _, ok := v.(cadence.TypeValue); return ok },
+	"Int":       func(v cadence.Value) bool { _, ok := v.(cadence.Int); return ok },
+	"Int8":      func(v cadence.Value) bool { _, ok := v.(cadence.Int8); return ok },
+	"Int16":     func(v cadence.Value) bool { _, ok := v.(cadence.Int16); return ok },
+	"Int32":     func(v cadence.Value) bool { _, ok := v.(cadence.Int32); return ok },
+	"Int64":     func(v cadence.Value) bool { _, ok := v.(cadence.Int64); return ok },
+	"Int128":    func(v cadence.Value) bool { _, ok := v.(cadence.Int128); return ok },
+	"Int256":    func(v cadence.Value) bool { _, ok := v.(cadence.Int256); return ok },
+	"UInt":      func(v cadence.Value) bool { _, ok := v.(cadence.UInt); return ok },
+	"UInt8":     func(v cadence.Value) bool { _, ok := v.(cadence.UInt8); return ok },
+	"UInt16":    func(v cadence.Value) bool { _, ok := v.(cadence.UInt16); return ok },
+	"UInt32":    func(v cadence.Value) bool { _, ok := v.(cadence.UInt32); return ok },
+	"UInt64":    func(v cadence.Value) bool { _, ok := v.(cadence.UInt64); return ok },
+	"UInt128":   func(v cadence.Value) bool { _, ok := v.(cadence.UInt128); return ok },
+	"UInt256":   func(v cadence.Value) bool { _, ok := v.(cadence.UInt256); return ok },
+	"Word8":     func(v cadence.Value) bool { _, ok := v.(cadence.Word8); return ok },
+	"Word16":    func(v cadence.Value) bool { _, ok := v.(cadence.Word16); return ok },
+	"Word32":    func(v cadence.Value) bool { _, ok := v.(cadence.Word32); return ok },
+	"Word64":    func(v cadence.Value) bool { _, ok := v.(cadence.Word64); return ok },
+	"Word128":   func(v cadence.Value) bool { _, ok := v.(cadence.Word128); return ok },
+	"Word256":   func(v cadence.Value) bool { _, ok := v.(cadence.Word256); return ok },
+	"Fix64":     func(v cadence.Value) bool { _, ok := v.(cadence.Fix64); return ok },
+	"UFix64":    func(v cadence.Value) bool { _, ok := v.(cadence.UFix64); return ok },
+	"Path":      func(v cadence.Value) bool { _, ok := v.(cadence.Path); return ok },
+	"StoragePath": func(v cadence.Value) bool {
+		p, ok := v.(cadence.Path)
+		return ok && p.Domain == common.PathDomainStorage
+	},
+	"PublicPath": func(v cadence.Value) bool {
+		p, ok := v.(cadence.Path)
+		return ok && p.Domain == common.PathDomainPublic
+	},
+	"PrivatePath": func(v cadence.Value) bool {
+		p, ok := v.(cadence.Path)
+		return ok && p.Domain == common.PathDomainPrivate
+	},
+	"CapabilityPath": func(v cadence.Value) bool {
+		p, ok := v.(cadence.Path)
+		return ok && (p.Domain == common.PathDomainPublic || p.Domain == common.PathDomainPrivate)
+	},
+}
+
+// valueMatchesNominalType only rejects values of concrete built-in types. Abstract types such as Number,
+// capabilities and user defined types, which may be interfaces, are left to the network.
+func valueMatchesNominalType(value cadence.Value, t *ast.NominalType) bool {
+	if len(t.NestedIdentifiers) > 0 {
+		return true
+	}
+	if check, ok := simpleTypeChecks[t.Identifier.Identifier]; ok {
+		return check(value)
+	}
+	return true
+}
+
+// cadenceTypeName returns the type of the value for error messages
+func cadenceTypeName(value cadence.Value) string {
+	if value == nil {
+		return "nil"
+	}
+	if optional, ok := value.(cadence.Optional); ok && optional.Value != nil {
+		return cadenceTypeName(optional.Value) + "?"
+	}
+	if value.Type() != nil {
+		return value.Type().ID()
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", value), "cadence.")
+}
+
+// namedArgument is an argument given by parameter name, placed at run time
+type namedArgument struct {
+	Name  string
+	Value cadence.Value
+}
+
+// resolveArguments places the named arguments after the positional ones, in the order of the parameters declared in the code
+func resolveArguments(code []byte, positional []cadence.Value, named []namedArgument) ([]cadence.Value, error) {
+	if len(named) == 0 {
+		return positional, nil
+	}
+
+	parameters, err := parseParameters(code)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]cadence.Value{}
+	for _, arg := range named {
+		if _, found := byName[arg.Name]; found {
+			return nil, fmt.Errorf("argument %s is given more than once", arg.Name)
+		}
+		byName[arg.Name] = arg.Value
+	}
+
+	result := make([]cadence.Value, 0, len(parameters))
+	for i, p := range parameters {
+		value, found := byName[p.Name]
+		if i < len(positional) {
+			if found {
+				return nil, fmt.Errorf("argument %s is given both by position and by name", p.Name)
+			}
+			result = append(result, positional[i])
+			continue
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: missing argument %s", ErrWrongArgumentCount, p.Name)
+		}
+		delete(byName, p.Name)
+		result = append(result, value)
+	}
+
+	if len(positional) > len(parameters) {
+		return nil, fmt.Errorf("%w: got %d positional arguments, the code takes %d", ErrWrongArgumentCount, len(positional), len(parameters))
+	}
+	for name := range byName {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownParameter, name)
+	}
+
+	return result, nil
+}
+
+// namedArguments converts a map of arguments to named arguments, sorted by name
+func namedArguments(arguments map[string]cadence.Value) []namedArgument {
+	result := make([]namedArgument, 0, len(arguments))
+	for name, value := range arguments {
+		result = append(result, namedArgument{Name: name, Value: value})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
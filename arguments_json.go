@@ -0,0 +1,171 @@
+package splash
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flowkit/v2/arguments"
+)
+
+// DecodeJSONArguments decodes a JSON array of JSON-Cadence values, the format of the --args-json flag of the flow CLI
+func DecodeJSONArguments(data []byte) ([]cadence.Value, error) {
+	values, err := arguments.ParseJSON(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON-Cadence arguments: %w", err)
+	}
+	return values, nil
+}
+
+// EncodeJSONArguments encodes arguments as an indented JSON array of JSON-Cadence values, see DecodeJSONArguments
+func EncodeJSONArguments(values []cadence.Value) ([]byte, error) {
+	encoded := make([]json.RawMessage, len(values))
+	for i, value := range values {
+		data, err := jsoncdc.Encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode argument %d: %w", i, err)
+		}
+		encoded[i] = bytes.TrimSpace(data)
+	}
+
+	return json.MarshalIndent(encoded, "", "  ")
+}
+
+// ReadArgumentsFile reads arguments from a file written by WriteArgumentsFile or for the flow CLI's --args-json flag
+func ReadArgumentsFile(fileName string) ([]cadence.Value, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	values, err := DecodeJSONArguments(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return values, nil
+}
+
+// WriteArgumentsFile writes arguments to a file as JSON-Cadence, see ReadArgumentsFile
+func WriteArgumentsFile(fileName string, values []cadence.Value) error {
+	data, err := EncodeJSONArguments(values)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, append(data, '\n'), 0o644)
+}
+
+// ArgumentsFromJSON adds the arguments of a JSON array of JSON-Cadence values to the transaction
+func (tb FlowTransactionBuilder) ArgumentsFromJSON(data []byte) FlowTransactionBuilder {
+	values, err := DecodeJSONArguments(data)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+	tb.Arguments = append(tb.Arguments, values...)
+	return tb
+}
+
+// ArgumentsFromFile adds the arguments stored in a JSON-Cadence file to the transaction, see ReadArgumentsFile
+func (tb FlowTransactionBuilder) ArgumentsFromFile(fileName string) FlowTransactionBuilder {
+	values, err := ReadArgumentsFile(fileName)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+	tb.Arguments = append(tb.Arguments, values...)
+	return tb
+}
+
+// ArgumentsJSON returns the arguments of the transaction as JSON-Cadence, with named arguments placed
+// in parameter order, see ArgumentsFromJSON
+func (tb FlowTransactionBuilder) ArgumentsJSON() ([]byte, error) {
+	args, err := tb.resolvedArguments()
+	if err != nil {
+		return nil, err
+	}
+	return EncodeJSONArguments(args)
+}
+
+// WriteArgumentsFile writes the arguments of the transaction to a JSON-Cadence file, see ArgumentsJSON
+func (tb FlowTransactionBuilder) WriteArgumentsFile(fileName string) error {
+	args, err := tb.resolvedArguments()
+	if err != nil {
+		return err
+	}
+	return WriteArgumentsFile(fileName, args)
+}
+
+// resolvedArguments returns the deferred builder error or the positional and named arguments in parameter order
+func (tb FlowTransactionBuilder) resolvedArguments() ([]cadence.Value, error) {
+	if tb.err != nil {
+		return nil, tb.err
+	}
+	if len(tb.namedArguments) == 0 {
+		return tb.Arguments, nil
+	}
+
+	code, err := tb.getContractCode(tb.codeFileName())
+	if err != nil {
+		return nil, err
+	}
+	return resolveArguments(code, tb.Arguments, tb.namedArguments)
+}
+
+// ArgumentsFromJSON adds the arguments of a JSON array of JSON-Cadence values to the script
+func (t FlowScriptBuilder) ArgumentsFromJSON(data []byte) FlowScriptBuilder {
+	values, err := DecodeJSONArguments(data)
+	if err != nil {
+		t.fail(err)
+		return t
+	}
+	t.Arguments = append(t.Arguments, values...)
+	return t
+}
+
+// ArgumentsFromFile adds the arguments stored in a JSON-Cadence file to the script, see ReadArgumentsFile
+func (t FlowScriptBuilder) ArgumentsFromFile(fileName string) FlowScriptBuilder {
+	values, err := ReadArgumentsFile(fileName)
+	if err != nil {
+		t.fail(err)
+		return t
+	}
+	t.Arguments = append(t.Arguments, values...)
+	return t
+}
+
+// ArgumentsJSON returns the arguments of the script as JSON-Cadence, with named arguments placed
+// in parameter order, see ArgumentsFromJSON
+func (t FlowScriptBuilder) ArgumentsJSON() ([]byte, error) {
+	args, err := t.resolvedArguments()
+	if err != nil {
+		return nil, err
+	}
+	return EncodeJSONArguments(args)
+}
+
+// WriteArgumentsFile writes the arguments of the script to a JSON-Cadence file, see ArgumentsJSON
+func (t FlowScriptBuilder) WriteArgumentsFile(fileName string) error {
+	args, err := t.resolvedArguments()
+	if err != nil {
+		return err
+	}
+	return WriteArgumentsFile(fileName, args)
+}
+
+// resolvedArguments returns the deferred builder error or the positional and named arguments in parameter order
+func (t FlowScriptBuilder) resolvedArguments() ([]cadence.Value, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	if len(t.namedArguments) == 0 {
+		return t.Arguments, nil
+	}
+
+	code, err := t.code()
+	if err != nil {
+		return nil, err
+	}
+	return resolveArguments(code, t.Arguments, t.namedArguments)
+}
@@ -0,0 +1,101 @@
+package splash_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/onflow/cadence"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONArguments(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	code := `
+access(all) fun main(name: String, amount: UFix64, ids: [UInt64]): String {
+  return name.concat(" ").concat(amount.toString()).concat(" ").concat(ids.length.toString())
+}`
+
+	t.Run("Read flow CLI arguments", func(t *testing.T) {
+		value, err := g.Script(code).ArgumentsFromJSON([]byte(`[
+  {"type": "String", "value": "alice"},
+  {"type": "UFix64", "value": "10.5"},
+  {"type": "Array", "value": [{"type": "UInt64", "value": "1"}, {"type": "UInt64", "value": "2"}]}
+]`)).RunReturns(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.String("alice 10.50000000 2"), value)
+	})
+
+	t.Run("Write and read argument files", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "args.json")
+
+		script := g.Script(code).StringArgument("bob").UFix64Argument("0.1").Args([]uint64{7})
+		require.NoError(t, script.WriteArgumentsFile(fileName))
+
+		replayed := g.Script(code).ArgumentsFromFile(fileName)
+		require.NoError(t, replayed.Err())
+		assert.Equal(t, script.Arguments, replayed.Arguments)
+
+		value, err := replayed.RunReturns(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.String("bob 0.10000000 1"), value)
+	})
+
+	t.Run("Encode struct arguments", func(t *testing.T) {
+		tx := g.Transaction(`
+import Debug from "../contracts/Debug.cdc"
+
+transaction(value: Debug.Foo) {}`).StructArgument("Debug.Foo", map[string]any{"bar": "baz"})
+
+		data, err := tx.ArgumentsJSON()
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{
+  "type": "Struct",
+  "value": {
+    "id": "A.f8d6e0586b0a20c7.Debug.Foo",
+    "fields": [{"name": "bar", "value": {"type": "String", "value": "baz"}}]
+  }
+}]`, string(data))
+
+		values, err := DecodeJSONArguments(data)
+		require.NoError(t, err)
+		require.Len(t, values, 1)
+		assert.Equal(t, "A.f8d6e0586b0a20c7.Debug.Foo", values[0].Type().ID())
+	})
+
+	t.Run("Write named arguments in parameter order", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "args.json")
+
+		script := g.Script(code).
+			NamedArgument("ids", cadence.NewArray([]cadence.Value{cadence.NewUInt64(7)})).
+			StringArgument("carol").
+			NamedArgument("amount", cadence.UFix64(100000000))
+		require.NoError(t, script.WriteArgumentsFile(fileName))
+
+		value, err := g.Script(code).ArgumentsFromFile(fileName).RunReturns(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.String("carol 1.00000000 1"), value)
+	})
+
+	t.Run("Fail on builder errors", func(t *testing.T) {
+		_, err := g.Script(code).Args(true).ArgumentsJSON()
+		assert.ErrorContains(t, err, "can't convert bool to String")
+
+		err = g.Transaction(`
+transaction(name: String) {}`).NamedArgument("nickname", cadence.String("dave")).WriteArgumentsFile(filepath.Join(t.TempDir(), "args.json"))
+		assert.ErrorIs(t, err, ErrWrongArgumentCount)
+	})
+
+	t.Run("Fail on invalid files", func(t *testing.T) {
+		err := g.Transaction("").ArgumentsFromJSON([]byte(`{"type": "String"}`)).Err()
+		assert.ErrorContains(t, err, "invalid JSON-Cadence arguments")
+
+		err = g.Script(code).ArgumentsFromFile(filepath.Join(t.TempDir(), "missing.json")).Err()
+		assert.Error(t, err)
+	})
+}
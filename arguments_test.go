@@ -0,0 +1,244 @@
+package splash_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/flow-go-sdk"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	t.Run("Integers follow the parameter types", func(t *testing.T) {
+		script := g.Script(`
+access(all) fun main(a: UInt64, b: Word64, c: Int, d: Int128, e: UInt8): Bool {
+  return true
+}`).Args(uint64(1), uint64(2), big.NewInt(3), "-4", 5)
+		require.NoError(t, script.Err())
+
+		i128, _ := cadence.NewInt128FromBig(big.NewInt(-4))
+		assert.Equal(t, []cadence.Value{
+			cadence.NewUInt64(1),
+			cadence.NewWord64(2),
+			cadence.NewInt(3),
+			i128,
+			cadence.NewUInt8(5),
+		}, script.Arguments)
+	})
+
+	t.Run("Collections, optionals and other types", func(t *testing.T) {
+		name := "alice"
+		address := flow.HexToAddress("0x01cf0e2f2f715450")
+		script := g.Script(`
+access(all) fun main(a: [String], b: {String: UInt32}, c: String?, d: Int?, e: Address, f: StoragePath, g: UFix64, h: Bool, i: [UInt8; 2]) {
+}`).Args(
+			[]string{"a", "b"},
+			map[string]int{"y": 2, "x": 1},
+			&name,
+			(*int)(nil),
+			address,
+			"/storage/foo",
+			time.Unix(1700000000, 500000000),
+			true,
+			[2]byte{1, 2},
+		)
+		require.NoError(t, script.Err())
+
+		fraction, _ := cadence.NewUFix64("1700000000.5")
+		assert.Equal(t, []cadence.Value{
+			cadence.NewArray([]cadence.Value{cadence.String("a"), cadence.String("b")}),
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{Key: cadence.String("x"), Value: cadence.NewUInt32(1)},
+				{Key: cadence.String("y"), Value: cadence.NewUInt32(2)},
+			}),
+			cadence.NewOptional(cadence.String("alice")),
+			cadence.NewOptional(nil),
+			cadence.NewAddress(address),
+			cadence.Path{Domain: common.PathDomainStorage, Identifier: "foo"},
+			fraction,
+			cadence.NewBool(true),
+			cadence.NewArray([]cadence.Value{cadence.NewUInt8(1), cadence.NewUInt8(2)}),
+		}, script.Arguments)
+	})
+
+	t.Run("Mix with typed argument methods", func(t *testing.T) {
+		tx := g.Transaction(`
+transaction(a: String, b: Word8) {
+}`).StringArgument("a").Args(8)
+		require.NoError(t, tx.Err())
+		assert.Equal(t, []cadence.Value{cadence.String("a"), cadence.NewWord8(8)}, tx.Arguments)
+	})
+
+	t.Run("Fail on out of range values", func(t *testing.T) {
+		err := g.Script(`access(all) fun main(a: UInt8) {}`).Args(256).Err()
+		assert.ErrorContains(t, err, "256 is out of range for UInt8")
+	})
+
+	t.Run("Fail on too many arguments", func(t *testing.T) {
+		err := g.Script(`access(all) fun main(a: UInt8) {}`).Args(1, 2).Err()
+		assert.ErrorIs(t, err, ErrWrongArgumentCount)
+	})
+
+	t.Run("Fail on unsupported conversions", func(t *testing.T) {
+		err := g.Script(`access(all) fun main(a: Bool) {}`).Args("yes").Err()
+		assert.ErrorContains(t, err, "can't convert string to Bool")
+	})
+}
+
+func TestArgumentValidation(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("Wrong argument type", func(t *testing.T) {
+		_, err := g.Script(`
+access(all) fun main(name: String, amount: UFix64): UFix64 {
+  return amount
+}`).StringArgument("alice").StringArgument("10.0").RunReturns(ctx)
+
+		var argumentError *ArgumentError
+		require.ErrorAs(t, err, &argumentError)
+		assert.Equal(t, 1, argumentError.Index)
+		assert.Equal(t, "amount", argumentError.Parameter)
+		assert.Equal(t, "UFix64", argumentError.Expected)
+		assert.Equal(t, "String", argumentError.Actual)
+		assert.EqualError(t, err, "argument 1 (amount): expected UFix64, got String")
+	})
+
+	t.Run("Wrong element type", func(t *testing.T) {
+		_, err := g.Script(`
+access(all) fun main(values: [UInt64]?): Bool {
+  return true
+}`).Argument(cadence.NewOptional(cadence.NewArray([]cadence.Value{cadence.NewUInt64(1), cadence.NewInt(2)}))).RunReturns(ctx)
+
+		var argumentError *ArgumentError
+		require.ErrorAs(t, err, &argumentError)
+		assert.Equal(t, "[UInt64]?", argumentError.Expected)
+	})
+
+	t.Run("Missing arguments are reported before sending the transaction", func(t *testing.T) {
+		_, err := g.Transaction(`
+transaction(recipient: Address, amount: UFix64) {
+  prepare(acct: &Account) {}
+}`).SignProposeAndPayAs("first").AccountArgument("second").RunE(ctx)
+
+		assert.ErrorIs(t, err, ErrWrongArgumentCount)
+		assert.ErrorContains(t, err, "expected 2 (recipient: Address, amount: UFix64), got 1")
+	})
+
+	t.Run("Matching arguments pass", func(t *testing.T) {
+		value, err := g.Script(`
+access(all) fun main(names: {String: [Int]}, path: StoragePath, flag: Bool?): Bool {
+  return true
+}`).Args(map[string][]int{"a": {1}}, "/storage/foo", nil).RunReturns(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewBool(true), value)
+	})
+
+	t.Run("Abstract types pass", func(t *testing.T) {
+		amount, err := cadence.NewUFix64("1.5")
+		require.NoError(t, err)
+
+		value, err := g.Script(`
+access(all) fun main(amount: Number): Number {
+  return amount
+}`).Argument(amount).RunReturns(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, amount, value)
+	})
+
+	t.Run("Capabilities are left to the network", func(t *testing.T) {
+		capability := cadence.NewCapability(1, cadence.Address(g.Account("first").Address), cadence.NewReferenceType(cadence.UnauthorizedAccess, cadence.AnyStructType))
+
+		_, err := g.Script(`
+access(all) fun main(capability: Capability): Bool {
+  return true
+}`).Argument(capability).RunReturns(ctx)
+
+		var argumentError *ArgumentError
+		assert.False(t, errors.As(err, &argumentError))
+		assert.ErrorContains(t, err, "argument type is not importable")
+	})
+}
+
+func TestNamedArguments(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	script := g.Script(`
+access(all) fun main(greeting: String, name: String, times: Int): String {
+  var result = ""
+  var i = 0
+  while i < times {
+    result = result.concat(greeting).concat(" ").concat(name).concat("! ")
+    i = i + 1
+  }
+  return result
+}`)
+
+	t.Run("Reorder named arguments", func(t *testing.T) {
+		value, err := script.
+			NamedArgument("times", cadence.NewInt(2)).
+			NamedArgument("name", cadence.String("Bob")).
+			NamedArgument("greeting", cadence.String("Hi")).
+			RunReturns(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.String("Hi Bob! Hi Bob! "), value)
+	})
+
+	t.Run("Mix positional and named arguments", func(t *testing.T) {
+		value, err := script.
+			StringArgument("Hello").
+			ArgumentsByName(map[string]cadence.Value{
+				"times": cadence.NewInt(1),
+				"name":  cadence.String("Alice"),
+			}).
+			RunReturns(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.String("Hello Alice! "), value)
+	})
+
+	t.Run("Fail on unknown names", func(t *testing.T) {
+		_, err := script.
+			ArgumentsByName(map[string]cadence.Value{
+				"greeting": cadence.String("Hi"),
+				"name":     cadence.String("Bob"),
+				"times":    cadence.NewInt(1),
+				"count":    cadence.NewInt(1),
+			}).
+			RunReturns(ctx)
+		assert.ErrorIs(t, err, ErrUnknownParameter)
+		assert.ErrorContains(t, err, "count")
+	})
+
+	t.Run("Fail on missing names", func(t *testing.T) {
+		_, err := script.
+			NamedArgument("greeting", cadence.String("Hi")).
+			NamedArgument("times", cadence.NewInt(1)).
+			RunReturns(ctx)
+		assert.ErrorIs(t, err, ErrWrongArgumentCount)
+		assert.ErrorContains(t, err, "missing argument name")
+	})
+
+	t.Run("Fail on arguments given twice", func(t *testing.T) {
+		_, err := script.
+			StringArgument("Hi").
+			NamedArgument("greeting", cadence.String("Hello")).
+			RunReturns(ctx)
+		assert.ErrorContains(t, err, "argument greeting is given both by position and by name")
+	})
+}
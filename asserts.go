@@ -17,13 +17,15 @@ type TransactionResult struct {
 }
 
 func (tb FlowTransactionBuilder) Test(t *testing.T) TransactionResult {
-	locale, _ := time.LoadLocation("UTC")
-	time.Local = locale
-	events, err := tb.RunE(context.Background())
-	formattedEvents := make([]*FormatedEvent, len(events))
-	for i, event := range events {
-		ev := ParseEvent(event, uint64(0), time.Unix(0, 0), []string{})
-		formattedEvents[i] = ev
+	res, err := tb.RunWithResultE(context.Background())
+	var formattedEvents []*FormatedEvent
+	if err == nil {
+		formattedEvents = make([]*FormatedEvent, len(res.Events))
+		for i, event := range res.Events {
+			ev := ParseEvent(event, res.BlockHeight, time.Unix(0, 0).UTC(), []string{})
+			ev.BlockID = res.BlockID
+			formattedEvents[i] = ev
+		}
 	}
 	return TransactionResult{
 		Err:     err,
@@ -80,7 +82,7 @@ func (t TransactionResult) AssertEmitEventName(event ...string) TransactionResul
 func (t TransactionResult) AssertEmitEventJSON(event ...string) TransactionResult {
 
 	jsonEvents := make([]string, len(t.Events))
-	for i, fe := range t.Events {
+	for i, fe := range t.eventsWithoutMetadata() {
 		jsonEvents[i] = fe.String()
 	}
 
@@ -104,9 +106,10 @@ func (t TransactionResult) AssertPartialEvent(expected *FormatedEvent) Transacti
 		return cpy
 	}
 
+	expected = expected.withoutMetadata()
 	expectedCpy := copyEvent(expected)
 
-	events := t.Events
+	events := t.eventsWithoutMetadata()
 NextEvent:
 	for _, ev := range events {
 		if ev.Name == expected.Name {
@@ -128,7 +131,7 @@ NextEvent:
 
 	assert.Contains(t.Testing, events, &expectedCpy)
 
-	for _, ev := range events {
+	for _, ev := range t.Events {
 		t.Testing.Log(ev.String())
 	}
 
@@ -137,8 +140,9 @@ NextEvent:
 
 func (t TransactionResult) AssertEmitEvent(event ...*FormatedEvent) TransactionResult {
 	printEvents := false
+	events := t.eventsWithoutMetadata()
 	for _, ev := range event {
-		if !assert.Contains(t.Testing, t.Events, ev) {
+		if !assert.Contains(t.Testing, events, ev.withoutMetadata()) {
 			printEvents = true
 		}
 	}
@@ -152,6 +156,16 @@ func (t TransactionResult) AssertEmitEvent(event ...*FormatedEvent) TransactionR
 	return t
 }
 
+// eventsWithoutMetadata returns the events without their transaction and block metadata, so that
+// they can be compared to events created with NewTestEvent
+func (t TransactionResult) eventsWithoutMetadata() []*FormatedEvent {
+	events := make([]*FormatedEvent, len(t.Events))
+	for i, ev := range t.Events {
+		events[i] = ev.withoutMetadata()
+	}
+	return events
+}
+
 func (t TransactionResult) PrintEvents() TransactionResult {
 	for _, ev := range t.Events {
 		t.Testing.Log(ev.String())
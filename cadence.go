@@ -2,10 +2,12 @@ package splash
 
 import (
 	"encoding/json"
-	"log"
+	"math/big"
+	"reflect"
 	"strconv"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
 )
 
 // CadenceValueToJSONString converts a cadence.Value into a json pretty printed string
@@ -18,14 +20,25 @@ func CadenceValueToJSONString(value cadence.Value) string {
 	j, err := json.MarshalIndent(result, "", "    ")
 
 	if err != nil {
-		log.Fatal(err)
+		panic(err)
 	}
 
 	return string(j)
 }
 
-// CadenceValueToInterface convert a cadence.Value into interface{}
-func CadenceValueToInterface(field cadence.Value) interface{} {
+// CadenceValueToInterface convert a cadence.Value into interface{}. By default values other than structs,
+// arrays, dictionaries and optionals are converted to strings. Pass WithTypedValues to keep their types.
+func CadenceValueToInterface(field cadence.Value, opts ...ConversionOption) interface{} {
+	if len(opts) > 0 {
+		options := conversionOptions{}
+		for _, opt := range opts {
+			opt(&options)
+		}
+		if options.typed {
+			return options.convert(field, "")
+		}
+	}
+
 	if field == nil {
 		return ""
 	}
@@ -65,3 +78,166 @@ func CadenceValueToInterface(field cadence.Value) interface{} {
 		return result
 	}
 }
+
+type (
+	// ConversionOption configures CadenceValueToInterface
+	ConversionOption func(*conversionOptions)
+
+	conversionOptions struct {
+		typed           bool
+		timestampFields map[string]bool
+	}
+)
+
+// WithTypedValues makes CadenceValueToInterface keep the types of the values instead of converting them to strings:
+//   - Int8-Int64 become int8-int64, UInt8-UInt64 and Word8-Word64 become uint8-uint64
+//   - Int, UInt and the 128 and 256 bit integers become *big.Int
+//   - UFix64 and Fix64 become Decimal
+//   - Address becomes flow.Address, Bool becomes bool, String and Character become string
+//   - empty optionals become nil
+//   - structs, resources, events, contracts, enums and attachments become map[string]interface{} of their fields
+//   - dictionaries become map[string]interface{} if all keys are strings, map[interface{}]interface{} otherwise,
+//     where keys that are big integers, decimals or enums are given as their Cadence string, e.g. "42"
+//   - paths and types become strings, capabilities and ranges become maps of their parts
+func WithTypedValues() ConversionOption {
+	return func(o *conversionOptions) {
+		o.typed = true
+	}
+}
+
+// WithTimestampFields converts the composite fields and dictionary entries with the given names from
+// Unix timestamps to time.Time. It implies WithTypedValues.
+func WithTimestampFields(names ...string) ConversionOption {
+	return func(o *conversionOptions) {
+		o.typed = true
+		if o.timestampFields == nil {
+			o.timestampFields = map[string]bool{}
+		}
+		for _, name := range names {
+			o.timestampFields[name] = true
+		}
+	}
+}
+
+// convert converts a value to a typed Go value. name is the field or key that holds the value, if any.
+func (o conversionOptions) convert(value cadence.Value, name string) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	if name != "" && o.timestampFields[name] {
+		if t, err := cadenceToTime(value); err == nil {
+			return t
+		}
+	}
+
+	switch v := value.(type) {
+	case cadence.Optional:
+		return o.convert(v.Value, name)
+	case cadence.String:
+		return string(v)
+	case cadence.Character:
+		return string(v)
+	case cadence.Bool:
+		return bool(v)
+	case cadence.Address:
+		return flow.Address(v)
+	case cadence.UFix64, cadence.Fix64:
+		decimal, _ := DecimalFromCadence(v)
+		return decimal
+	case interface{ Big() *big.Int }:
+		return v.Big()
+	case cadence.Path:
+		return v.String()
+	case cadence.TypeValue:
+		if v.StaticType == nil {
+			return ""
+		}
+		return v.StaticType.ID()
+	case cadence.Capability:
+		result := map[string]interface{}{
+			"address": flow.Address(v.Address),
+			"id":      uint64(v.ID),
+		}
+		if v.BorrowType != nil {
+			result["borrowType"] = v.BorrowType.ID()
+		}
+		return result
+	case *cadence.InclusiveRange:
+		return map[string]interface{}{
+			"start": o.convert(v.Start, ""),
+			"end":   o.convert(v.End, ""),
+			"step":  o.convert(v.Step, ""),
+		}
+	case cadence.Composite:
+		result := map[string]interface{}{}
+		for fieldName, fieldValue := range cadence.FieldsMappedByName(v) {
+			result[fieldName] = o.convert(fieldValue, fieldName)
+		}
+		return result
+	case cadence.Array:
+		result := make([]interface{}, len(v.Values))
+		for i, item := range v.Values {
+			result[i] = o.convert(item, "")
+		}
+		return result
+	case cadence.Dictionary:
+		return o.convertDictionary(v)
+	case cadence.Void, cadence.Function:
+		return nil
+	}
+
+	// the remaining values are fixed size integers, which are defined on the matching Go types
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int8:
+		return int8(rv.Int())
+	case reflect.Int16:
+		return int16(rv.Int())
+	case reflect.Int32:
+		return int32(rv.Int())
+	case reflect.Int64:
+		return rv.Int()
+	case reflect.Uint8:
+		return uint8(rv.Uint())
+	case reflect.Uint16:
+		return uint16(rv.Uint())
+	case reflect.Uint32:
+		return uint32(rv.Uint())
+	case reflect.Uint64:
+		return rv.Uint()
+	}
+
+	return value.String()
+}
+
+func (o conversionOptions) convertDictionary(dictionary cadence.Dictionary) interface{} {
+	stringKeys := map[string]interface{}{}
+	for _, pair := range dictionary.Pairs {
+		key, ok := pair.Key.(cadence.String)
+		if !ok {
+			stringKeys = nil
+			break
+		}
+		stringKeys[string(key)] = o.convert(pair.Value, string(key))
+	}
+	if stringKeys != nil {
+		return stringKeys
+	}
+
+	result := map[interface{}]interface{}{}
+	for _, pair := range dictionary.Pairs {
+		result[o.dictionaryKey(pair.Key)] = o.convert(pair.Value, "")
+	}
+	return result
+}
+
+// dictionaryKey converts a dictionary key to a value that compares by value. Big integers, decimals and
+// enums don't, so they are keyed by their Cadence representation, e.g. "42", "1.50000000" or "A.1.Foo.Color(rawValue: 1)".
+func (o conversionOptions) dictionaryKey(key cadence.Value) interface{} {
+	switch converted := o.convert(key, "").(type) {
+	case string, bool, int8, int16, int32, int64, uint8, uint16, uint32, uint64, flow.Address:
+		return converted
+	}
+	return key.String()
+}
@@ -1,11 +1,16 @@
 package splash_test
 
 import (
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/flow-go-sdk"
 	. "github.com/piprate/splash"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCadenceValueToJSONString(t *testing.T) {
@@ -73,6 +78,82 @@ func TestCadenceValueToJSONString(t *testing.T) {
 	})
 }
 
+func TestCadenceValueToInterfaceTyped(t *testing.T) {
+
+	t.Parallel()
+	price, _ := cadence.NewUFix64("12.5")
+	created, _ := cadence.NewUFix64("1700000000.5")
+	address := flow.HexToAddress("0x01cf0e2f2f715450")
+
+	event := cadence.NewEvent([]cadence.Value{
+		cadence.NewUInt64(1),
+		price,
+		cadence.NewAddress(address),
+		cadence.NewOptional(nil),
+		cadence.NewInt(42),
+		created,
+		cadence.Path{Domain: common.PathDomainStorage, Identifier: "foo"},
+	}).WithType(cadence.NewEventType(nil, "A.0000000000000001.Market.Listed", []cadence.Field{
+		{Identifier: "id", Type: cadence.UInt64Type},
+		{Identifier: "price", Type: cadence.UFix64Type},
+		{Identifier: "seller", Type: cadence.AddressType},
+		{Identifier: "buyer", Type: cadence.NewOptionalType(cadence.AddressType)},
+		{Identifier: "amount", Type: cadence.IntType},
+		{Identifier: "created", Type: cadence.UFix64Type},
+		{Identifier: "path", Type: cadence.StoragePathType},
+	}, nil))
+
+	t.Run("Keep value types", func(t *testing.T) {
+		value, ok := CadenceValueToInterface(event, WithTypedValues()).(map[string]interface{})
+		require.True(t, ok)
+
+		assert.Equal(t, uint64(1), value["id"])
+		assert.Equal(t, address, value["seller"])
+		assert.Nil(t, value["buyer"])
+		assert.Equal(t, big.NewInt(42), value["amount"])
+		assert.Equal(t, "/storage/foo", value["path"])
+
+		decimal, ok := value["price"].(Decimal)
+		require.True(t, ok)
+		assert.Equal(t, "12.50000000", decimal.String())
+		assert.IsType(t, Decimal{}, value["created"])
+	})
+
+	t.Run("Convert timestamp fields", func(t *testing.T) {
+		value := CadenceValueToInterface(event, WithTimestampFields("created")).(map[string]interface{})
+		assert.Equal(t, time.Unix(1700000000, 500000000).UTC(), value["created"])
+	})
+
+	t.Run("Dictionary keys compare by value", func(t *testing.T) {
+		color := cadence.NewEnum([]cadence.Value{cadence.NewUInt8(1)}).WithType(cadence.NewEnumType(nil, "A.0000000000000001.Paint.Color", cadence.UInt8Type, []cadence.Field{
+			{Identifier: "rawValue", Type: cadence.UInt8Type},
+		}, nil))
+		enumKeys := cadence.NewDictionary([]cadence.KeyValuePair{{Key: color, Value: cadence.NewUInt64(7)}})
+
+		value, ok := CadenceValueToInterface(enumKeys, WithTypedValues()).(map[interface{}]interface{})
+		require.True(t, ok)
+		assert.Equal(t, uint64(7), value[color.String()])
+
+		large, err := cadence.NewUInt256FromBig(new(big.Int).Lsh(big.NewInt(1), 200))
+		require.NoError(t, err)
+		bigKeys := cadence.NewDictionary([]cadence.KeyValuePair{{Key: large, Value: cadence.String("large")}})
+
+		value, ok = CadenceValueToInterface(bigKeys, WithTypedValues()).(map[interface{}]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "large", value[new(big.Int).Lsh(big.NewInt(1), 200).String()])
+
+		smallKeys := cadence.NewDictionary([]cadence.KeyValuePair{{Key: cadence.NewUInt32(3), Value: cadence.String("small")}})
+		value, ok = CadenceValueToInterface(smallKeys, WithTypedValues()).(map[interface{}]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "small", value[uint32(3)])
+	})
+
+	t.Run("Default conversion is unchanged", func(t *testing.T) {
+		assert.Equal(t, "12.50000000", CadenceValueToInterface(price))
+		assert.Equal(t, "1", CadenceValueToInterface(cadence.NewUInt64(1)))
+	})
+}
+
 func NewCadenceString(value string) cadence.String {
 	cadenceValue, err := cadence.NewString(value)
 	if err != nil {
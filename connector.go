@@ -2,9 +2,13 @@ package splash
 
 import (
 	"fmt"
-	"log"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/access"
 	grpcAccess "github.com/onflow/flow-go-sdk/access/grpc"
 	"github.com/onflow/flowkit/v2"
@@ -24,6 +28,20 @@ type Connector struct {
 	Network                      string
 	Logger                       output.Logger
 	PrependNetworkToAccountNames bool
+	// RetryPolicy is applied to transactions, scripts and event queries unless overridden by a builder
+	RetryPolicy RetryPolicy
+	// ScriptCache caches the results of scripts if set, see WithScriptCache
+	ScriptCache ScriptCache
+
+	keyPoolsMu sync.RWMutex
+	keyPools   map[flow.Address]*ProposalKeyPool
+
+	scriptCacheHits   atomic.Uint64
+	scriptCacheMisses atomic.Uint64
+
+	// the in-memory emulator can't execute scripts concurrently, so they are run one at a time
+	serialScripts bool
+	scriptMu      sync.Mutex
 }
 
 // maxGRPCMessageSize 60mb
@@ -64,10 +82,13 @@ func NewNetworkConnector(paths []string, baseLoader flowkit.ReaderWriter, networ
 		Logger:                       logger,
 		PrependNetworkToAccountNames: true,
 		Network:                      network,
+		RetryPolicy:                  DefaultRetryPolicy(),
 	}, nil
 }
 
-func NewInMemoryConnector(paths []string, baseLoader flowkit.ReaderWriter, enableTxFees bool, logger output.Logger) (*Connector, error) {
+// NewInMemoryConnector creates a connector backed by an in-memory emulator. Emulator options, e.g.
+// emulator.WithTransactionExpiry for transactions that are built concurrently, are passed on to the emulator.
+func NewInMemoryConnector(paths []string, baseLoader flowkit.ReaderWriter, enableTxFees bool, logger output.Logger, emulatorOptions ...emulator.Option) (*Connector, error) {
 
 	state, err := flowkit.Load(paths, baseLoader)
 	if err != nil {
@@ -82,12 +103,10 @@ func NewInMemoryConnector(paths []string, baseLoader flowkit.ReaderWriter, enabl
 		SigAlgo:   acc.Key.SigAlgo(),
 		HashAlgo:  acc.Key.HashAlgo(),
 	}
-	var gw *gateway.EmulatorGateway
 	if enableTxFees {
-		gw = gateway.NewEmulatorGatewayWithOpts(key, gateway.WithEmulatorOptions(emulator.WithTransactionFeesEnabled(true)))
-	} else {
-		gw = gateway.NewEmulatorGateway(key)
+		emulatorOptions = append(emulatorOptions, emulator.WithTransactionFeesEnabled(true))
 	}
+	gw := gateway.NewEmulatorGatewayWithOpts(key, gateway.WithEmulatorOptions(emulatorOptions...))
 	service := flowkit.NewFlowkit(state, config.EmulatorNetwork, gw, logger)
 
 	return &Connector{
@@ -96,6 +115,8 @@ func NewInMemoryConnector(paths []string, baseLoader flowkit.ReaderWriter, enabl
 		Logger:                       logger,
 		PrependNetworkToAccountNames: true,
 		Network:                      "emulator",
+		RetryPolicy:                  NoRetryPolicy(),
+		serialScripts:                true,
 	}, nil
 }
 
@@ -105,8 +126,8 @@ func NewConnectorDefault(network string, logLevel int) (*Connector, error) {
 	return NewNetworkConnector(config.DefaultPaths(), loader, network, stdoutLogger)
 }
 
-func NewInMemoryTestConnector(baseDir string, enableTxFees bool) (*Connector, error) {
-	return NewInMemoryConnector([]string{config.DefaultPath}, NewFileSystemLoader(baseDir), enableTxFees, NewZeroLogger())
+func NewInMemoryTestConnector(baseDir string, enableTxFees bool, emulatorOptions ...emulator.Option) (*Connector, error) {
+	return NewInMemoryConnector([]string{config.DefaultPath}, NewFileSystemLoader(baseDir), enableTxFees, NewZeroLogger(), emulatorOptions...)
 }
 
 // DoNotPrependNetworkToAccountNames disable the default behavior of prefixing account names with network-
@@ -115,16 +136,78 @@ func (c *Connector) DoNotPrependNetworkToAccountNames() *Connector {
 	return c
 }
 
-// Account fetch an account from flow.json, prefixing the name with network- as default (can be turned off)
+// WithRetryPolicy sets the default retry policy of the connector
+func (c *Connector) WithRetryPolicy(policy RetryPolicy) *Connector {
+	c.RetryPolicy = policy
+	return c
+}
+
+// Account fetch an account from flow.json, prefixing the name with network- as default (can be turned off).
+// It panics if the account doesn't exist.
 func (c *Connector) Account(key string) *accounts.Account {
+	account, err := c.AccountE(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return account
+}
+
+// AccountE fetch an account from flow.json, prefixing the name with network- as default (can be turned off)
+// and returns ErrUnknownAccount if it doesn't exist.
+func (c *Connector) AccountE(key string) (*accounts.Account, error) {
 	if c.PrependNetworkToAccountNames {
 		key = fmt.Sprintf("%s-%s", c.Services.Network().Name, key)
 	}
 
-	account, err := c.State.Accounts().ByName(key)
+	return c.accountByFullName(key)
+}
+
+func (c *Connector) accountByFullName(name string) (*accounts.Account, error) {
+	account, err := c.State.Accounts().ByName(name)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("%w: %w", ErrUnknownAccount, err)
 	}
 
-	return account
+	return account, nil
+}
+
+// ContractAddresses returns the addresses of the contracts on the connector's network, taken from
+// the contract aliases and the deployments in flow.json
+func (c *Connector) ContractAddresses() (map[string]flow.Address, error) {
+	network := c.Services.Network()
+	deployedContracts, err := c.State.DeploymentContractsByNetwork(network)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make(map[string]flow.Address)
+	for _, contract := range *c.State.Contracts() {
+		for _, alias := range contract.Aliases {
+			if alias.Network == network.Name {
+				addresses[contract.Name] = alias.Address
+			}
+		}
+	}
+	for _, contract := range deployedContracts {
+		addresses[strings.Split(path.Base(contract.Location()), ".")[0]] = contract.AccountAddress
+	}
+
+	return addresses, nil
+}
+
+// ContractAddress returns the address of a contract on the connector's network, or ErrUnknownContract
+// if the contract is neither aliased nor deployed there
+func (c *Connector) ContractAddress(contractName string) (flow.Address, error) {
+	addresses, err := c.ContractAddresses()
+	if err != nil {
+		return flow.EmptyAddress, err
+	}
+
+	address, found := addresses[contractName]
+	if !found {
+		return flow.EmptyAddress, fmt.Errorf("%w: %s on %s", ErrUnknownContract, contractName, c.Services.Network().Name)
+	}
+
+	return address, nil
 }
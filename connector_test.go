@@ -10,6 +10,7 @@ import (
 	. "github.com/piprate/splash"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,3 +40,19 @@ func TestNewInMemoryConnector_WithTxFees(t *testing.T) {
 	_, err = client.DoNotPrependNetworkToAccountNames().CreateAccountsE(ctx, "emulator-account")
 	require.NoError(t, err)
 }
+
+func TestConnector_Account(t *testing.T) {
+	client, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	account, err := client.AccountE("first")
+	require.NoError(t, err)
+	assert.Equal(t, "emulator-first", account.Name)
+
+	_, err = client.AccountE("nobody")
+	assert.ErrorIs(t, err, ErrUnknownAccount)
+
+	assert.Panics(t, func() {
+		client.Account("nobody")
+	})
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/araddon/dateparse"
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/common"
 )
 
+// ToFloat64 converts a UFix64 value to a float64, which may lose precision. Use DecimalFromCadence for exact values.
 func ToFloat64(value cadence.Value) float64 {
 	val, _ := strconv.ParseFloat(value.(cadence.UFix64).String(), 64)
 	return val
@@ -24,14 +27,44 @@ func UFix64ToString(v float64) string {
 	return vStr + ".0"
 }
 
+// UFix64FromFloat64 converts a float64 to a UFix64 value, rounded to 8 decimal places.
+// Use Decimal for amounts that must be exact.
 func UFix64FromFloat64(v float64) cadence.Value {
-	cv, err := cadence.NewUFix64(fmt.Sprintf("%.4f", v))
+	cv, err := cadence.NewUFix64(strconv.FormatFloat(v, 'f', decimalScale, 64))
 	if err != nil {
 		panic(err)
 	}
 	return cv
 }
 
+// TimeToUFix64 converts a time to a UFix64 Unix timestamp, the format of getCurrentBlock().timestamp in Cadence.
+// Fractional seconds are kept to 8 decimal places. Times before 1970 return an error.
+func TimeToUFix64(t time.Time) (cadence.UFix64, error) {
+	timestamp, err := timestampDecimal(t, false)
+	if err != nil {
+		return 0, fmt.Errorf("%s can't be a UFix64 timestamp: %w", t.Format(time.RFC3339Nano), err)
+	}
+	return timestamp.UFix64()
+}
+
+// ParseTimeInLocation parses a date string in any format supported by dateparse. Dates without a time zone
+// are interpreted in the given location. Unlike changing time.Local, this has no effect on the rest of the process.
+func ParseTimeInLocation(dateString string, location *time.Location) (time.Time, error) {
+	if location == nil {
+		return time.Time{}, errors.New("missing location")
+	}
+	return dateparse.ParseIn(dateString, location)
+}
+
+// parseTimeInZone parses a date string in the named time zone, e.g. "America/New_York"
+func parseTimeInZone(dateString, timezone string) (time.Time, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ParseTimeInLocation(dateString, location)
+}
+
 func StringToPath(path string) (cadence.Path, error) {
 	var val cadence.Path
 	parts := strings.Split(path, "/")
@@ -89,3 +122,34 @@ func ExtractUInt64ValueFromEvent(txResult TransactionResult, eventName, key stri
 
 	panic(fmt.Sprintf("value not found for %s in %s", key, eventName))
 }
+
+// ExtractDecimalValueFromEvent returns a UFix64 or Fix64 field of an event as a Decimal. Fields formatted
+// as strings are parsed as UFix64, or as Fix64 if they are negative.
+func ExtractDecimalValueFromEvent(txResult TransactionResult, eventName, key string) Decimal {
+	for _, e := range txResult.Events {
+		if e.Name == eventName {
+			v := e.Fields[key]
+			if v == nil {
+				panic(fmt.Sprintf("key %s not found in %s", key, eventName))
+			}
+			switch val := v.(type) {
+			case string:
+				parse := ParseUFix64
+				if strings.HasPrefix(val, "-") {
+					parse = ParseFix64
+				}
+				res, err := parse(val)
+				if err != nil {
+					panic(err)
+				}
+				return res
+			case Decimal:
+				return val
+			default:
+				panic(fmt.Sprintf("unexpected value type for %s in %s: %T", key, eventName, v))
+			}
+		}
+	}
+
+	panic(fmt.Sprintf("value not found for %s in %s", key, eventName))
+}
@@ -2,9 +2,11 @@ package splash_test
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/piprate/splash"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUFix64ToString(t *testing.T) {
@@ -28,3 +30,68 @@ func TestStringToPath(t *testing.T) {
 	_, err = StringToPath("/storage/bad/test")
 	assert.Error(t, err)
 }
+
+func TestUFix64FromFloat64(t *testing.T) {
+	assert.Equal(t, "10.12345678", UFix64FromFloat64(10.12345678).String())
+	assert.Equal(t, "0.10000000", UFix64FromFloat64(0.1).String())
+}
+
+func TestExtractDecimalValueFromEvent(t *testing.T) {
+	result := TransactionResult{Events: []*FormatedEvent{
+		NewTestEvent("A.1.Token.Deposited", map[string]interface{}{"amount": "10.12345678", "delta": "-0.5"}),
+	}}
+
+	assert.Equal(t, "10.12345678", ExtractDecimalValueFromEvent(result, "A.1.Token.Deposited", "amount").String())
+	delta := ExtractDecimalValueFromEvent(result, "A.1.Token.Deposited", "delta")
+	assert.True(t, delta.Signed())
+	assert.Equal(t, "-0.50000000", delta.String())
+}
+
+func TestDateArguments(t *testing.T) {
+	local := time.Local
+
+	t.Run("Keep fractions of a second", func(t *testing.T) {
+		value, err := TimeToUFix64(time.Unix(1627560000, 123456789))
+		require.NoError(t, err)
+		assert.Equal(t, "1627560000.12345678", value.String())
+	})
+
+	t.Run("Fail on times before 1970", func(t *testing.T) {
+		_, err := TimeToUFix64(time.Unix(-1, 0))
+		assert.ErrorIs(t, err, ErrUnderflow)
+	})
+
+	t.Run("Parse in a location", func(t *testing.T) {
+		newYork, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+
+		value, err := ParseTimeInLocation("July 29, 2021 08:00:00.5 AM", newYork)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2021, 7, 29, 12, 0, 0, 500000000, time.UTC), value.UTC())
+
+		_, err = ParseTimeInLocation("July 29, 2021", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Builders don't change the local time zone", func(t *testing.T) {
+		g, err := NewInMemoryTestConnector("examples", false)
+		require.NoError(t, err)
+
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+
+		builder := g.Transaction("").
+			DateStringAsUnixTimestamp("2021-07-29 21:00:00", "Asia/Tokyo").
+			DateStringArgument("2021-07-29 21:00:00.25", tokyo).
+			DateArgument(time.Unix(1627560000, 0))
+		require.NoError(t, builder.Err())
+
+		assert.Equal(t, "1627560000.00000000", builder.Arguments[0].String())
+		assert.Equal(t, "1627560000.25000000", builder.Arguments[1].String())
+		assert.Equal(t, "1627560000.00000000", builder.Arguments[2].String())
+		assert.Same(t, local, time.Local)
+
+		err = g.Script("").DateStringAsUnixTimestamp("not a date", "UTC").Err()
+		assert.Error(t, err)
+	})
+}
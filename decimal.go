@@ -0,0 +1,235 @@
+package splash
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/fixedpoint"
+)
+
+// decimalScale is the number of decimal places of Cadence fixed-point numbers
+const decimalScale = 8
+
+var (
+	decimalFactor = big.NewInt(100_000_000)
+
+	maxUFix64Raw = new(big.Int).SetUint64(math.MaxUint64)
+	minFix64Raw  = big.NewInt(math.MinInt64)
+	maxFix64Raw  = big.NewInt(math.MaxInt64)
+)
+
+// Decimal is an exact fixed-point number with 8 decimal places, like Cadence's UFix64 and Fix64.
+// Signed decimals follow Fix64, unsigned decimals follow UFix64. The zero value is an unsigned zero.
+//
+// Arithmetic follows Cadence: the result has the type of the operands, multiplication and division
+// round like the Cadence interpreter does, and results out of range return ErrOverflow or ErrUnderflow
+// instead of wrapping around.
+type Decimal struct {
+	// raw is the value multiplied by 10^8
+	raw    *big.Int
+	signed bool
+}
+
+// ParseUFix64 parses a decimal string such as "12.5" or "12" as a UFix64 decimal
+func ParseUFix64(s string) (Decimal, error) {
+	raw, err := fixedpoint.ParseUFix64(withDecimalPoint(s))
+	if err != nil {
+		return Decimal{}, fmt.Errorf("invalid UFix64 %q: %w", s, err)
+	}
+	return Decimal{raw: raw}, nil
+}
+
+// ParseFix64 parses a decimal string such as "-12.5" or "12" as a Fix64 decimal
+func ParseFix64(s string) (Decimal, error) {
+	raw, err := fixedpoint.ParseFix64(withDecimalPoint(s))
+	if err != nil {
+		return Decimal{}, fmt.Errorf("invalid Fix64 %q: %w", s, err)
+	}
+	return Decimal{raw: raw, signed: true}, nil
+}
+
+func withDecimalPoint(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, ".") {
+		return s + ".0"
+	}
+	return s
+}
+
+// DecimalFromCadence converts a UFix64 or Fix64 value to a Decimal
+func DecimalFromCadence(value cadence.Value) (Decimal, error) {
+	switch v := value.(type) {
+	case cadence.UFix64:
+		return Decimal{raw: new(big.Int).SetUint64(uint64(v))}, nil
+	case cadence.Fix64:
+		return Decimal{raw: big.NewInt(int64(v)), signed: true}, nil
+	}
+
+	return Decimal{}, fmt.Errorf("can't convert %s to a decimal", cadenceTypeName(value))
+}
+
+// newDecimal checks that a raw value fits into UFix64 or Fix64
+func newDecimal(raw *big.Int, signed bool) (Decimal, error) {
+	if signed {
+		if raw.Cmp(minFix64Raw) < 0 {
+			return Decimal{}, ErrUnderflow
+		}
+		if raw.Cmp(maxFix64Raw) > 0 {
+			return Decimal{}, ErrOverflow
+		}
+	} else {
+		if raw.Sign() < 0 {
+			return Decimal{}, ErrUnderflow
+		}
+		if raw.Cmp(maxUFix64Raw) > 0 {
+			return Decimal{}, ErrOverflow
+		}
+	}
+	return Decimal{raw: raw, signed: signed}, nil
+}
+
+// timestampDecimal returns a time as a Unix timestamp with the fraction of the second truncated to 8 decimals
+func timestampDecimal(t time.Time, signed bool) (Decimal, error) {
+	raw := new(big.Int).Mul(big.NewInt(t.Unix()), decimalFactor)
+	raw.Add(raw, big.NewInt(int64(t.Nanosecond()/10)))
+	return newDecimal(raw, signed)
+}
+
+// Signed returns true if the decimal follows Fix64 rather than UFix64
+func (d Decimal) Signed() bool {
+	return d.signed
+}
+
+func (d Decimal) rawValue() *big.Int {
+	if d.raw == nil {
+		return new(big.Int)
+	}
+	return d.raw
+}
+
+// Cadence returns the decimal as a cadence.UFix64 or cadence.Fix64 value
+func (d Decimal) Cadence() cadence.Value {
+	if d.signed {
+		return cadence.Fix64(d.rawValue().Int64())
+	}
+	return cadence.UFix64(d.rawValue().Uint64())
+}
+
+// UFix64 returns the decimal as a UFix64 value, failing for negative values
+func (d Decimal) UFix64() (cadence.UFix64, error) {
+	value, err := newDecimal(d.rawValue(), false)
+	if err != nil {
+		return 0, fmt.Errorf("%s doesn't fit into UFix64: %w", d, err)
+	}
+	return cadence.UFix64(value.raw.Uint64()), nil
+}
+
+// Fix64 returns the decimal as a Fix64 value, failing for values above the Fix64 maximum
+func (d Decimal) Fix64() (cadence.Fix64, error) {
+	value, err := newDecimal(d.rawValue(), true)
+	if err != nil {
+		return 0, fmt.Errorf("%s doesn't fit into Fix64: %w", d, err)
+	}
+	return cadence.Fix64(value.raw.Int64()), nil
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	if err := d.checkOperand(other); err != nil {
+		return Decimal{}, err
+	}
+	return newDecimal(new(big.Int).Add(d.rawValue(), other.rawValue()), d.signed)
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) (Decimal, error) {
+	if err := d.checkOperand(other); err != nil {
+		return Decimal{}, err
+	}
+	return newDecimal(new(big.Int).Sub(d.rawValue(), other.rawValue()), d.signed)
+}
+
+// Mul returns d * other
+func (d Decimal) Mul(other Decimal) (Decimal, error) {
+	if err := d.checkOperand(other); err != nil {
+		return Decimal{}, err
+	}
+	result := new(big.Int).Mul(d.rawValue(), other.rawValue())
+	return newDecimal(result.Div(result, decimalFactor), d.signed)
+}
+
+// Div returns d / other
+func (d Decimal) Div(other Decimal) (Decimal, error) {
+	if err := d.checkOperand(other); err != nil {
+		return Decimal{}, err
+	}
+	if other.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+	result := new(big.Int).Mul(d.rawValue(), decimalFactor)
+	return newDecimal(result.Div(result, other.rawValue()), d.signed)
+}
+
+func (d Decimal) checkOperand(other Decimal) error {
+	if d.signed != other.signed {
+		return fmt.Errorf("can't mix %s and %s decimals", d.typeName(), other.typeName())
+	}
+	return nil
+}
+
+func (d Decimal) typeName() string {
+	if d.signed {
+		return "Fix64"
+	}
+	return "UFix64"
+}
+
+// Cmp compares the values of two decimals and returns -1, 0 or +1
+func (d Decimal) Cmp(other Decimal) int {
+	return d.rawValue().Cmp(other.rawValue())
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the decimal
+func (d Decimal) Sign() int {
+	return d.rawValue().Sign()
+}
+
+// IsZero returns true if the decimal is zero
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// String formats the decimal with all 8 decimal places, like Cadence does
+func (d Decimal) String() string {
+	raw := d.rawValue()
+
+	abs := new(big.Int).Abs(raw)
+	integer, fraction := new(big.Int).QuoRem(abs, decimalFactor, new(big.Int))
+
+	sign := ""
+	if raw.Sign() < 0 {
+		sign = "-"
+	}
+
+	fractionDigits := fraction.String()
+	return sign + integer.String() + "." + strings.Repeat("0", decimalScale-len(fractionDigits)) + fractionDigits
+}
+
+// MarshalJSON encodes the decimal as a JSON string, so that no precision is lost
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalCadence decodes a UFix64 or Fix64 value, see DecodeCadence
+func (d *Decimal) UnmarshalCadence(value cadence.Value) error {
+	decimal, err := DecimalFromCadence(value)
+	if err != nil {
+		return err
+	}
+	*d = decimal
+	return nil
+}
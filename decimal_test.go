@@ -0,0 +1,141 @@
+package splash_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/onflow/cadence"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseUFix64(t *testing.T, s string) Decimal {
+	t.Helper()
+	d, err := ParseUFix64(s)
+	require.NoError(t, err)
+	return d
+}
+
+func mustParseFix64(t *testing.T, s string) Decimal {
+	t.Helper()
+	d, err := ParseFix64(s)
+	require.NoError(t, err)
+	return d
+}
+
+func TestDecimal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Parse and format", func(t *testing.T) {
+		assert.Equal(t, "12.00000000", mustParseUFix64(t, "12").String())
+		assert.Equal(t, "0.12345678", mustParseUFix64(t, "0.12345678").String())
+		assert.Equal(t, "184467440737.09551615", mustParseUFix64(t, "184467440737.09551615").String())
+		assert.Equal(t, "-1.50000000", mustParseFix64(t, "-1.5").String())
+		assert.Equal(t, "0.00000000", Decimal{}.String())
+
+		_, err := ParseUFix64("-1.0")
+		assert.Error(t, err)
+		_, err = ParseUFix64("184467440737.09551616")
+		assert.Error(t, err)
+		_, err = ParseUFix64("1.123456789")
+		assert.Error(t, err)
+		_, err = ParseFix64("abc")
+		assert.Error(t, err)
+	})
+
+	t.Run("Convert to and from Cadence", func(t *testing.T) {
+		value, _ := cadence.NewUFix64("10.25")
+		d, err := DecimalFromCadence(value)
+		require.NoError(t, err)
+		assert.False(t, d.Signed())
+		assert.Equal(t, value, d.Cadence())
+
+		fix, err := d.Fix64()
+		require.NoError(t, err)
+		assert.Equal(t, "10.25000000", fix.String())
+
+		_, err = mustParseFix64(t, "-1.0").UFix64()
+		assert.ErrorIs(t, err, ErrUnderflow)
+
+		_, err = DecimalFromCadence(cadence.NewInt(1))
+		assert.Error(t, err)
+	})
+
+	t.Run("Arithmetic", func(t *testing.T) {
+		a := mustParseUFix64(t, "10.5")
+		b := mustParseUFix64(t, "0.00000003")
+
+		sum, err := a.Add(b)
+		require.NoError(t, err)
+		assert.Equal(t, "10.50000003", sum.String())
+
+		difference, err := a.Sub(b)
+		require.NoError(t, err)
+		assert.Equal(t, "10.49999997", difference.String())
+
+		product, err := a.Mul(mustParseUFix64(t, "3"))
+		require.NoError(t, err)
+		assert.Equal(t, "31.50000000", product.String())
+
+		quotient, err := mustParseUFix64(t, "1").Div(mustParseUFix64(t, "3"))
+		require.NoError(t, err)
+		assert.Equal(t, "0.33333333", quotient.String())
+
+		assert.Equal(t, 1, a.Cmp(b))
+		assert.Equal(t, 0, a.Cmp(mustParseFix64(t, "10.5")))
+	})
+
+	t.Run("Overflow semantics", func(t *testing.T) {
+		_, err := mustParseUFix64(t, "1").Sub(mustParseUFix64(t, "2"))
+		assert.ErrorIs(t, err, ErrUnderflow)
+
+		_, err = mustParseUFix64(t, "184467440737.0").Add(mustParseUFix64(t, "1"))
+		assert.ErrorIs(t, err, ErrOverflow)
+
+		_, err = mustParseFix64(t, "-92233720368.0").Mul(mustParseFix64(t, "2"))
+		assert.ErrorIs(t, err, ErrUnderflow)
+
+		_, err = mustParseUFix64(t, "1").Div(Decimal{})
+		assert.ErrorIs(t, err, ErrDivisionByZero)
+
+		_, err = mustParseUFix64(t, "1").Add(mustParseFix64(t, "1"))
+		assert.EqualError(t, err, "can't mix UFix64 and Fix64 decimals")
+	})
+
+	t.Run("Encode as a JSON string", func(t *testing.T) {
+		data, err := json.Marshal(map[string]Decimal{"amount": mustParseUFix64(t, "0.1")})
+		require.NoError(t, err)
+		assert.Equal(t, `{"amount":"0.10000000"}`, string(data))
+	})
+}
+
+func TestDecimalMatchesCadence(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	a := mustParseFix64(t, "-7.00000001")
+	b := mustParseFix64(t, "0.3")
+
+	value, err := g.Script(`
+access(all) fun main(a: Fix64, b: Fix64): [Fix64] {
+  return [a * b, a / b]
+}`).DecimalArgument(a).DecimalArgument(b).RunReturns(ctx)
+	require.NoError(t, err)
+
+	product, err := a.Mul(b)
+	require.NoError(t, err)
+	quotient, err := a.Div(b)
+	require.NoError(t, err)
+
+	assert.Equal(t, cadence.NewArray([]cadence.Value{product.Cadence(), quotient.Cadence()}), value)
+
+	value, err = g.Script(`
+access(all) fun main(amount: UFix64): UFix64 {
+  return amount
+}`).Args(mustParseUFix64(t, "123456.12345678")).RunReturns(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "123456.12345678", value.String())
+}
@@ -0,0 +1,382 @@
+package splash
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// CadenceUnmarshaler is implemented by types that decode themselves from a Cadence value
+type CadenceUnmarshaler interface {
+	UnmarshalCadence(value cadence.Value) error
+}
+
+// DecodeError is returned when a Cadence value can't be decoded into the Go target
+type DecodeError struct {
+	// Path locates the value inside the decoded value, e.g. "items[2].price"
+	Path    string
+	Cadence string
+	Go      string
+	Reason  string
+}
+
+func (e *DecodeError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "value"
+	}
+	message := fmt.Sprintf("%s: can't decode %s into %s", path, e.Cadence, e.Go)
+	if e.Reason != "" {
+		message += ": " + e.Reason
+	}
+	return message
+}
+
+var (
+	cadenceValueType       = reflect.TypeOf((*cadence.Value)(nil)).Elem()
+	cadenceUnmarshalerType = reflect.TypeOf((*CadenceUnmarshaler)(nil)).Elem()
+	flowAddressType        = reflect.TypeOf(flow.Address{})
+	cadenceAddressType     = reflect.TypeOf(cadence.Address{})
+	timeType               = reflect.TypeOf(time.Time{})
+	bigIntValueType        = reflect.TypeOf(big.Int{})
+)
+
+// DecodeCadence decodes a Cadence value into the Go value pointed to by target.
+//
+// Composites (structs, resources, events, contracts and enums) are decoded into Go structs, matching
+// fields by the `cadence:"name"` tag or, without a tag, by the Go field name (case-insensitive).
+// Fields tagged `cadence:"-"` are skipped. Composites and dictionaries can also be decoded into maps,
+// arrays into slices and Go arrays, and optionals into pointers (nil for an empty optional).
+// Integers decode into any Go integer type that can hold them and into *big.Int, fixed-point
+// numbers into float64 or string, addresses into flow.Address or string, and numbers into time.Time
+// as Unix timestamps. Targets of a cadence.Value type receive the value as is.
+func DecodeCadence(value cadence.Value, target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("the decoding target must be a non-nil pointer, got %T", target)
+	}
+
+	return decodeCadence(value, rv.Elem(), "")
+}
+
+func decodeCadence(value cadence.Value, rv reflect.Value, path string) error {
+	// cadence value targets take the value as is, including optionals
+	if rv.Type().Implements(cadenceValueType) && (value == nil || reflect.TypeOf(value).AssignableTo(rv.Type())) {
+		if value == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+		} else {
+			rv.Set(reflect.ValueOf(value))
+		}
+		return nil
+	}
+
+	if optional, ok := value.(cadence.Optional); ok {
+		if optional.Value == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		return decodeCadence(optional.Value, rv, path)
+	}
+	if value == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+
+	if rv.Kind() != reflect.Pointer && rv.CanAddr() && rv.Addr().Type().Implements(cadenceUnmarshalerType) {
+		if err := rv.Addr().Interface().(CadenceUnmarshaler).UnmarshalCadence(value); err != nil {
+			return decodeError(value, rv, path, err.Error())
+		}
+		return nil
+	}
+
+	switch rv.Type() {
+	case flowAddressType, cadenceAddressType:
+		address, ok := value.(cadence.Address)
+		if !ok {
+			return decodeError(value, rv, path, "")
+		}
+		rv.Set(reflect.ValueOf(address).Convert(rv.Type()))
+		return nil
+	case timeType:
+		t, err := cadenceToTime(value)
+		if err != nil {
+			return decodeError(value, rv, path, err.Error())
+		}
+		rv.Set(reflect.ValueOf(t))
+		return nil
+	case bigIntValueType:
+		n, ok := cadenceToBigInt(value)
+		if !ok {
+			return decodeError(value, rv, path, "")
+		}
+		rv.Set(reflect.ValueOf(n).Elem())
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeCadence(value, rv.Elem(), path)
+
+	case reflect.Interface:
+		if rv.NumMethod() > 0 {
+			return decodeError(value, rv, path, "")
+		}
+		rv.Set(reflect.ValueOf(CadenceValueToInterface(value)))
+		return nil
+
+	case reflect.Struct:
+		composite, ok := value.(cadence.Composite)
+		if !ok {
+			return decodeError(value, rv, path, "")
+		}
+		return decodeComposite(composite, rv, path)
+
+	case reflect.Map:
+		return decodeMap(value, rv, path)
+
+	case reflect.Slice, reflect.Array:
+		array, ok := value.(cadence.Array)
+		if !ok {
+			return decodeError(value, rv, path, "")
+		}
+		if rv.Kind() == reflect.Array {
+			if rv.Len() != len(array.Values) {
+				return decodeError(value, rv, path, fmt.Sprintf("expected %d elements, got %d", rv.Len(), len(array.Values)))
+			}
+		} else {
+			rv.Set(reflect.MakeSlice(rv.Type(), len(array.Values), len(array.Values)))
+		}
+		for i, element := range array.Values {
+			if err := decodeCadence(element, rv.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.String:
+		s, ok := cadenceToString(value)
+		if !ok {
+			return decodeError(value, rv, path, "")
+		}
+		rv.SetString(s)
+		return nil
+
+	case reflect.Bool:
+		b, ok := value.(cadence.Bool)
+		if !ok {
+			return decodeError(value, rv, path, "")
+		}
+		rv.SetBool(bool(b))
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := cadenceToBigInt(value)
+		if !ok {
+			return decodeError(value, rv, path, "")
+		}
+		if !n.IsInt64() || rv.OverflowInt(n.Int64()) {
+			return decodeError(value, rv, path, fmt.Sprintf("%s overflows", n))
+		}
+		rv.SetInt(n.Int64())
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := cadenceToBigInt(value)
+		if !ok {
+			return decodeError(value, rv, path, "")
+		}
+		if !n.IsUint64() || rv.OverflowUint(n.Uint64()) {
+			return decodeError(value, rv, path, fmt.Sprintf("%s overflows", n))
+		}
+		rv.SetUint(n.Uint64())
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		switch value.(type) {
+		case cadence.UFix64, cadence.Fix64:
+		default:
+			if _, ok := cadenceToBigInt(value); !ok {
+				return decodeError(value, rv, path, "")
+			}
+		}
+		f, err := strconv.ParseFloat(value.String(), 64)
+		if err != nil {
+			return decodeError(value, rv, path, err.Error())
+		}
+		rv.SetFloat(f)
+		return nil
+	}
+
+	return decodeError(value, rv, path, "")
+}
+
+func decodeComposite(composite cadence.Composite, rv reflect.Value, path string) error {
+	fields := cadence.FieldsMappedByName(composite)
+
+	lowerCaseFields := make(map[string]string, len(fields))
+	for name := range fields {
+		lowerCaseFields[strings.ToLower(name)] = name
+	}
+
+	structType := rv.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, hasTag := field.Tag.Lookup("cadence")
+		if name == "-" {
+			continue
+		}
+		if !hasTag || name == "" {
+			name = lowerCaseFields[strings.ToLower(field.Name)]
+		}
+
+		fieldValue, found := fields[name]
+		if !found {
+			continue
+		}
+
+		if err := decodeCadence(fieldValue, rv.Field(i), joinPath(path, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeMap(value cadence.Value, rv reflect.Value, path string) error {
+	mapType := rv.Type()
+	result := reflect.MakeMap(mapType)
+
+	switch v := value.(type) {
+	case cadence.Dictionary:
+		for _, pair := range v.Pairs {
+			key := reflect.New(mapType.Key()).Elem()
+			if err := decodeCadence(pair.Key, key, path+"{key}"); err != nil {
+				return err
+			}
+			element := reflect.New(mapType.Elem()).Elem()
+			if err := decodeCadence(pair.Value, element, fmt.Sprintf("%s[%v]", path, key)); err != nil {
+				return err
+			}
+			result.SetMapIndex(key, element)
+		}
+	case cadence.Composite:
+		if mapType.Key().Kind() != reflect.String {
+			return decodeError(value, rv, path, "composites can only be decoded into maps with string keys")
+		}
+		for name, fieldValue := range cadence.FieldsMappedByName(v) {
+			element := reflect.New(mapType.Elem()).Elem()
+			if err := decodeCadence(fieldValue, element, joinPath(path, name)); err != nil {
+				return err
+			}
+			result.SetMapIndex(reflect.ValueOf(name).Convert(mapType.Key()), element)
+		}
+	default:
+		return decodeError(value, rv, path, "")
+	}
+
+	rv.Set(result)
+	return nil
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func decodeError(value cadence.Value, rv reflect.Value, path, reason string) error {
+	return &DecodeError{
+		Path:    path,
+		Cadence: cadenceTypeName(value),
+		Go:      rv.Type().String(),
+		Reason:  reason,
+	}
+}
+
+// cadenceToBigInt returns the value of a Cadence integer or the raw value of an enum
+func cadenceToBigInt(value cadence.Value) (*big.Int, bool) {
+	switch v := value.(type) {
+	case interface{ Big() *big.Int }:
+		return v.Big(), true
+	case cadence.Enum:
+		if raw := cadence.SearchFieldByName(v, "rawValue"); raw != nil {
+			return cadenceToBigInt(raw)
+		}
+		return nil, false
+	case cadence.UFix64, cadence.Fix64:
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), true
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), true
+	}
+
+	return nil, false
+}
+
+func cadenceToString(value cadence.Value) (string, bool) {
+	switch v := value.(type) {
+	case cadence.String:
+		return string(v), true
+	case cadence.Character:
+		return string(v), true
+	case cadence.Address:
+		return v.String(), true
+	case cadence.Path, cadence.UFix64, cadence.Fix64:
+		return v.String(), true
+	case cadence.TypeValue:
+		if v.StaticType == nil {
+			return "", true
+		}
+		return v.StaticType.ID(), true
+	}
+
+	return "", false
+}
+
+// cadenceToTime converts Unix timestamps (integers or fixed-point numbers) and RFC 3339 strings to a time
+func cadenceToTime(value cadence.Value) (time.Time, error) {
+	switch v := value.(type) {
+	case cadence.String:
+		return time.Parse(time.RFC3339Nano, string(v))
+	case cadence.UFix64, cadence.Fix64:
+		seconds, fraction, _ := strings.Cut(v.String(), ".")
+		s, err := strconv.ParseInt(seconds, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		nanos, err := strconv.ParseInt((fraction + "000000000")[:9], 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if strings.HasPrefix(seconds, "-") {
+			nanos = -nanos
+		}
+		return time.Unix(s, nanos).UTC(), nil
+	}
+
+	n, ok := cadenceToBigInt(value)
+	if !ok || !n.IsInt64() {
+		return time.Time{}, errors.New("not a timestamp")
+	}
+	return time.Unix(n.Int64(), 0).UTC(), nil
+}
@@ -0,0 +1,139 @@
+package splash_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodedItem struct {
+	ID    uint64 `cadence:"id"`
+	Name  string
+	Price float64 `cadence:"price"`
+}
+
+type decodedListing struct {
+	Owner    flow.Address        `cadence:"owner"`
+	Items    []decodedItem       `cadence:"items"`
+	Tags     map[string]*big.Int `cadence:"tags"`
+	Note     *string             `cadence:"note"`
+	Missing  *string             `cadence:"missing"`
+	Created  time.Time           `cadence:"created"`
+	Kind     uint8               `cadence:"kind"`
+	Raw      cadence.Value       `cadence:"price"`
+	Ignored  string              `cadence:"-"`
+	Unmapped int
+}
+
+func TestRunInto(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	var listing decodedListing
+	err = g.Script(`
+access(all) enum Kind: UInt8 {
+  access(all) case fixed
+  access(all) case auction
+}
+
+access(all) struct Item {
+  access(all) let id: UInt64
+  access(all) let name: String
+  access(all) let price: UFix64
+
+  init(id: UInt64, name: String, price: UFix64) {
+    self.id = id
+    self.name = name
+    self.price = price
+  }
+}
+
+access(all) struct Listing {
+  access(all) let owner: Address
+  access(all) let items: [Item]
+  access(all) let tags: {String: Int}
+  access(all) let note: String?
+  access(all) let missing: String?
+  access(all) let created: UFix64
+  access(all) let kind: Kind
+  access(all) let price: UFix64
+
+  init() {
+    self.owner = 0x01cf0e2f2f715450
+    self.items = [Item(id: 1, name: "Sword", price: 12.5), Item(id: 2, name: "Shield", price: 7.25)]
+    self.tags = {"rare": 1}
+    self.note = "hello"
+    self.missing = nil
+    self.created = 1700000000.5
+    self.kind = Kind.auction
+    self.price = 19.75
+  }
+}
+
+access(all) fun main(): Listing {
+  return Listing()
+}`).RunInto(ctx, &listing)
+	require.NoError(t, err)
+
+	note := "hello"
+	price, _ := cadence.NewUFix64("19.75")
+	assert.Equal(t, decodedListing{
+		Owner: flow.HexToAddress("0x01cf0e2f2f715450"),
+		Items: []decodedItem{
+			{ID: 1, Name: "Sword", Price: 12.5},
+			{ID: 2, Name: "Shield", Price: 7.25},
+		},
+		Tags:    map[string]*big.Int{"rare": big.NewInt(1)},
+		Note:    &note,
+		Created: time.Unix(1700000000, 500000000).UTC(),
+		Kind:    1,
+		Raw:     price,
+	}, listing)
+}
+
+func TestDecodeCadence(t *testing.T) {
+	t.Run("Report the path of mismatched values", func(t *testing.T) {
+		value := cadence.NewArray([]cadence.Value{cadence.NewInt(1), cadence.String("two")})
+
+		var target []int
+		err := DecodeCadence(value, &target)
+
+		var decodeError *DecodeError
+		require.ErrorAs(t, err, &decodeError)
+		assert.Equal(t, "[1]", decodeError.Path)
+		assert.EqualError(t, err, "[1]: can't decode String into int")
+	})
+
+	t.Run("Report overflows", func(t *testing.T) {
+		var target uint8
+		err := DecodeCadence(cadence.NewUInt64(300), &target)
+		assert.EqualError(t, err, "value: can't decode UInt64 into uint8: 300 overflows")
+	})
+
+	t.Run("Decode dictionaries", func(t *testing.T) {
+		value := cadence.NewDictionary([]cadence.KeyValuePair{
+			{Key: cadence.NewUInt64(1), Value: cadence.NewOptional(nil)},
+			{Key: cadence.NewUInt64(2), Value: cadence.NewOptional(cadence.NewBool(true))},
+		})
+
+		var target map[uint64]*bool
+		require.NoError(t, DecodeCadence(value, &target))
+		require.Len(t, target, 2)
+		assert.Nil(t, target[1])
+		assert.True(t, *target[2])
+	})
+
+	t.Run("Reject non pointer targets", func(t *testing.T) {
+		var target string
+		assert.Error(t, DecodeCadence(cadence.String("a"), target))
+	})
+}
@@ -0,0 +1,32 @@
+package splash
+
+import (
+	"errors"
+)
+
+var (
+	// ErrUnknownAccount is returned when an account name can't be resolved from flow.json
+	ErrUnknownAccount = errors.New("unknown account")
+	// ErrMissingProposer is returned when a transaction is run without a proposer
+	ErrMissingProposer = errors.New("you need to set the proposer")
+	// ErrMissingPayer is returned when a transaction is run without a payer
+	ErrMissingPayer = errors.New("you need to set the payer")
+	// ErrUnknownContract is returned when a contract has no address on the current network
+	ErrUnknownContract = errors.New("unknown contract")
+	// ErrTemplateNotFound is returned when a template engine has no template with the given ID
+	ErrTemplateNotFound = errors.New("template not found")
+	// ErrTransactionExpired is returned when waiting for a transaction that has expired
+	ErrTransactionExpired = errors.New("transaction expired")
+	// ErrWrongArgumentCount is returned when the number of arguments doesn't match the parameters of a transaction or script
+	ErrWrongArgumentCount = errors.New("wrong number of arguments")
+	// ErrUnknownParameter is returned when a named argument doesn't match any parameter of a transaction or script
+	ErrUnknownParameter = errors.New("unknown parameter")
+	// ErrInvalidPassphrase is returned when a keystore file can't be decrypted with the given passphrase
+	ErrInvalidPassphrase = errors.New("invalid keystore passphrase")
+	// ErrOverflow is returned when a decimal operation exceeds the maximum of UFix64 or Fix64
+	ErrOverflow = errors.New("overflow")
+	// ErrUnderflow is returned when a decimal operation goes below the minimum of UFix64 or Fix64
+	ErrUnderflow = errors.New("underflow")
+	// ErrDivisionByZero is returned when a decimal is divided by zero
+	ErrDivisionByZero = errors.New("division by zero")
+)
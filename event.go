@@ -23,8 +23,15 @@ type EventFetcherBuilder struct {
 	EndAtCurrentHeight    bool
 	EndIndex              uint64
 	ProgressFile          string
+	ProgressStore         ProgressStore
+	ProgressKey           string
 	NumberOfWorkers       int
 	EventBatchSize        uint64
+	PollingInterval       time.Duration
+
+	retryPolicy     *RetryPolicy
+	eventPredicates map[string][]EventPredicate
+	err             error
 }
 
 // EventFetcher create an event fetcher builder.
@@ -37,7 +44,33 @@ func (c *Connector) EventFetcher() EventFetcherBuilder {
 		ProgressFile:          "",
 		EventBatchSize:        250,
 		NumberOfWorkers:       20,
+		PollingInterval:       time.Second,
+	}
+}
+
+func (e *EventFetcherBuilder) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// Retry overrides the retry policy of the connector for this event fetcher.
+func (e EventFetcherBuilder) Retry(policy RetryPolicy) EventFetcherBuilder {
+	e.retryPolicy = &policy
+	return e
+}
+
+// NoRetry disables retries for this event fetcher.
+func (e EventFetcherBuilder) NoRetry() EventFetcherBuilder {
+	return e.Retry(NoRetryPolicy())
+}
+
+// RetryPolicy returns the retry policy in effect for this event fetcher.
+func (e EventFetcherBuilder) RetryPolicy() RetryPolicy {
+	if e.retryPolicy != nil {
+		return *e.retryPolicy
 	}
+	return e.Connector.RetryPolicy
 }
 
 // Workers sets the number of workers.
@@ -104,9 +137,12 @@ func (e EventFetcherBuilder) UntilCurrent() EventFetcherBuilder {
 	return e
 }
 
-// TrackProgressIn Specify a file to store progress in
+// TrackProgressIn Specify a file to store progress in, see FileProgressStore. Writers are serialised with
+// a lock file of the same name with a .lock suffix, which is left next to the progress file.
 func (e EventFetcherBuilder) TrackProgressIn(fileName string) EventFetcherBuilder {
 	e.ProgressFile = fileName
+	e.ProgressStore = nil
+	e.ProgressKey = ""
 	e.EndIndex = 0
 	e.FromIndex = 0
 	e.EndAtCurrentHeight = true
@@ -148,40 +184,63 @@ func ReadProgressFromFile(fileName string) (int64, error) {
 	return strconv.ParseInt(stringValue, 10, 64)
 }
 
-// Run runs the eventfetcher returning events or an error
-func (e EventFetcherBuilder) Run(ctx context.Context) ([]*FormatedEvent, error) {
+// loadProgress sets FromIndex to the height stored in the progress store, saving an initial height if needed
+func (e EventFetcherBuilder) loadProgress() (EventFetcherBuilder, error) {
+	store, key := e.progressStore()
+	if store == nil {
+		return e, nil
+	}
 
-	// if we have a progress file read the value from it and set it as oldHeight
-	if e.ProgressFile != "" {
+	height, found, err := store.Load(key)
+	if err != nil {
+		return e, err
+	}
 
-		present, err := exists(e.ProgressFile)
+	if !found {
+		err := store.Save(key, 0)
 		if err != nil {
-			return nil, err
+			return e, fmt.Errorf("could not create initial progress %w", err)
 		}
 
-		if !present {
-			err := WriteProgressToFile(e.ProgressFile, 0)
-			if err != nil {
-				return nil, fmt.Errorf("could not create initial progress file %w", err)
-			}
+		e.FromIndex = 0
+	} else {
+		e.FromIndex = int64(height) //nolint:gosec
+	}
 
-			e.FromIndex = 0
-		} else {
-			oldHeight, err := ReadProgressFromFile(e.ProgressFile)
-			if err != nil {
-				return nil, fmt.Errorf("could not parse progress file as block height %w", err)
-			}
-			e.FromIndex = oldHeight
-		}
+	return e, nil
+}
+
+// Run runs the eventfetcher returning events or an error
+func (e EventFetcherBuilder) Run(ctx context.Context) ([]*FormatedEvent, error) {
+	formattedEvents, endIndex, err := e.run(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.saveProgress(endIndex + 1); err != nil {
+		return nil, err
+	}
+
+	return formattedEvents, nil
+}
+
+// run fetches the events of Run and returns them with the last block height fetched, without saving progress
+func (e EventFetcherBuilder) run(ctx context.Context) ([]*FormatedEvent, uint64, error) {
+	if e.err != nil {
+		return nil, 0, e.err
+	}
+
+	e, err := e.loadProgress()
+	if err != nil {
+		return nil, 0, err
 	}
 
 	endIndex := e.EndIndex
 	if e.EndAtCurrentHeight {
-		block, err := e.Connector.Services.GetBlock(ctx, flowkit.LatestBlockQuery)
+		endIndex, err = e.latestHeight(ctx)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
-		endIndex = block.Height
 	}
 
 	fromIndex := e.FromIndex
@@ -191,35 +250,53 @@ func (e EventFetcherBuilder) Run(ctx context.Context) ([]*FormatedEvent, error)
 	}
 
 	if fromIndex < 0 {
-		return nil, fmt.Errorf("FromIndex is negative")
+		return nil, 0, fmt.Errorf("FromIndex is negative")
 	}
 
 	e.Connector.Logger.Info(fmt.Sprintf("Fetching events from %d to %d", fromIndex, endIndex))
 
+	formattedEvents, err := e.fetch(ctx, uint64(fromIndex), endIndex)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return formattedEvents, endIndex, nil
+}
+
+// latestHeight returns the height of the latest sealed block
+func (e EventFetcherBuilder) latestHeight(ctx context.Context) (uint64, error) {
+	var block *flow.Block
+	err := e.Connector.retry(ctx, e.RetryPolicy(), "latest block query", func() (err error) {
+		block, err = e.Connector.Services.GetBlock(ctx, flowkit.LatestBlockQuery)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return block.Height, nil
+}
+
+// fetch returns the events of the blocks between the given heights, inclusive, in the order they were emitted
+func (e EventFetcherBuilder) fetch(ctx context.Context, fromIndex, toIndex uint64) ([]*FormatedEvent, error) {
 	events := make([]string, 0, len(e.EventsAndIgnoreFields))
 	for key := range e.EventsAndIgnoreFields {
 		events = append(events, key)
 	}
 
-	blockEvents, err := e.Connector.Services.GetEvents(ctx, events, uint64(fromIndex), endIndex, &flowkit.EventWorker{
-		Count:           e.NumberOfWorkers,
-		BlocksPerWorker: e.EventBatchSize,
+	var blockEvents []flow.BlockEvents
+	err := e.Connector.retry(ctx, e.RetryPolicy(), "event query", func() (err error) {
+		blockEvents, err = e.Connector.Services.GetEvents(ctx, events, fromIndex, toIndex, &flowkit.EventWorker{
+			Count:           e.NumberOfWorkers,
+			BlocksPerWorker: e.EventBatchSize,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	formattedEvents := FormatEvents(blockEvents, e.EventsAndIgnoreFields)
-
-	if e.ProgressFile != "" {
-		err := WriteProgressToFile(e.ProgressFile, endIndex+1)
-		if err != nil {
-			return nil, fmt.Errorf("could not write progress to file %w", err)
-		}
-	}
-	sort.Slice(formattedEvents, func(i, j int) bool {
-		return formattedEvents[i].BlockHeight < formattedEvents[j].BlockHeight
-	})
+	formattedEvents := formatEvents(blockEvents, e.EventsAndIgnoreFields, e.matches)
+	sortEvents(formattedEvents)
 
 	return formattedEvents, nil
 }
@@ -250,11 +327,23 @@ func PrintEvents(events []flow.Event, ignoreFields map[string][]string) {
 
 // FormatEvents
 func FormatEvents(blockEvents []flow.BlockEvents, ignoreFields map[string][]string) []*FormatedEvent {
+	return formatEvents(blockEvents, ignoreFields, nil)
+}
+
+// formatEvents formats the events kept by the keep function, if any, which sees all the fields of the events
+func formatEvents(blockEvents []flow.BlockEvents, ignoreFields map[string][]string, keep func(*FormatedEvent) bool) []*FormatedEvent {
 	var events []*FormatedEvent
 
 	for _, blockEvent := range blockEvents {
 		for _, event := range blockEvent.Events {
-			ev := ParseEvent(event, blockEvent.Height, blockEvent.BlockTimestamp, ignoreFields[event.Type])
+			ev := ParseEvent(event, blockEvent.Height, blockEvent.BlockTimestamp, nil)
+			if keep != nil && !keep(ev) {
+				continue
+			}
+			for _, field := range ignoreFields[event.Type] {
+				delete(ev.Fields, field)
+			}
+			ev.BlockID = blockEvent.BlockID
 			events = append(events, ev)
 		}
 	}
@@ -282,29 +371,116 @@ func ParseEvent(event flow.Event, blockHeight uint64, time time.Time, ignoreFiel
 		finalFields[name] = CadenceValueToInterface(field)
 	}
 	return &FormatedEvent{
-		Name:        event.Type,
-		Fields:      finalFields,
-		BlockHeight: blockHeight,
-		Time:        time,
+		Name:             event.Type,
+		Fields:           finalFields,
+		BlockHeight:      blockHeight,
+		Time:             time,
+		TransactionID:    event.TransactionID,
+		TransactionIndex: event.TransactionIndex,
+		EventIndex:       event.EventIndex,
 	}
 }
 
 // FormatedEvent event in a more condensed formated form
 type FormatedEvent struct {
-	Name        string                 `json:"name"`
-	BlockHeight uint64                 `json:"blockHeight,omitempty"`
-	Time        time.Time              `json:"time,omitempty"`
-	Fields      map[string]interface{} `json:"fields"`
+	Name        string
+	BlockHeight uint64
+	BlockID     flow.Identifier
+	Time        time.Time
+	// TransactionID, TransactionIndex and EventIndex identify the event and its position in the block
+	TransactionID    flow.Identifier
+	TransactionIndex int
+	EventIndex       int
+	Fields           map[string]interface{}
+}
+
+// formatedEventJSON is the JSON form of a FormatedEvent, leaving out the block and transaction when they aren't known
+type formatedEventJSON struct {
+	Name             string                 `json:"name"`
+	BlockHeight      uint64                 `json:"blockHeight,omitempty"`
+	BlockID          string                 `json:"blockId,omitempty"`
+	Time             time.Time              `json:"time,omitempty"`
+	TransactionID    string                 `json:"transactionId,omitempty"`
+	TransactionIndex *int                   `json:"transactionIndex,omitempty"`
+	EventIndex       *int                   `json:"eventIndex,omitempty"`
+	Fields           map[string]interface{} `json:"fields"`
+}
+
+// MarshalJSON encodes the event, leaving out metadata that isn't set
+func (e FormatedEvent) MarshalJSON() ([]byte, error) {
+	ev := formatedEventJSON{
+		Name:        e.Name,
+		BlockHeight: e.BlockHeight,
+		Time:        e.Time,
+		Fields:      e.Fields,
+	}
+	if e.BlockID != flow.EmptyID {
+		ev.BlockID = e.BlockID.Hex()
+	}
+	if e.TransactionID != flow.EmptyID {
+		ev.TransactionID = e.TransactionID.Hex()
+		ev.TransactionIndex = &e.TransactionIndex
+		ev.EventIndex = &e.EventIndex
+	}
+	return json.Marshal(ev)
+}
+
+// UnmarshalJSON decodes an event encoded with MarshalJSON
+func (e *FormatedEvent) UnmarshalJSON(data []byte) error {
+	var ev formatedEventJSON
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return err
+	}
+	*e = FormatedEvent{
+		Name:        ev.Name,
+		BlockHeight: ev.BlockHeight,
+		Time:        ev.Time,
+		Fields:      ev.Fields,
+	}
+	if ev.BlockID != "" {
+		e.BlockID = flow.HexToID(ev.BlockID)
+	}
+	if ev.TransactionID != "" {
+		e.TransactionID = flow.HexToID(ev.TransactionID)
+	}
+	if ev.TransactionIndex != nil {
+		e.TransactionIndex = *ev.TransactionIndex
+	}
+	if ev.EventIndex != nil {
+		e.EventIndex = *ev.EventIndex
+	}
+	return nil
+}
+
+// withoutMetadata returns a copy of the event without its block and transaction IDs, heights and positions
+func (e FormatedEvent) withoutMetadata() *FormatedEvent {
+	e.BlockHeight = 0
+	e.BlockID = flow.EmptyID
+	e.TransactionID = flow.EmptyID
+	e.TransactionIndex = 0
+	e.EventIndex = 0
+	return &e
+}
+
+// sortEvents sorts events by block height, transaction index and event index
+func sortEvents(events []*FormatedEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if a.BlockHeight != b.BlockHeight {
+			return a.BlockHeight < b.BlockHeight
+		}
+		if a.TransactionIndex != b.TransactionIndex {
+			return a.TransactionIndex < b.TransactionIndex
+		}
+		return a.EventIndex < b.EventIndex
+	})
 }
 
 func NewTestEvent(name string, fields map[string]interface{}) *FormatedEvent {
-	loc, _ := time.LoadLocation("UTC")
-	// handle err
-	time.Local = loc // -> this is setting the global timezone
 	return &FormatedEvent{
 		Name:        name,
 		BlockHeight: 0,
-		Time:        time.Unix(0, 0),
+		Time:        time.Unix(0, 0).UTC(),
 		Fields:      fields,
 	}
 }
@@ -0,0 +1,325 @@
+package splash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// EventPredicate decides whether an event is kept by an event fetcher
+type EventPredicate func(event *FormatedEvent) bool
+
+// EventWhere fetches an event, keeping only those matching all given predicates. It can be called several times
+// for the same event; predicates are applied before ignored fields are removed.
+func (e EventFetcherBuilder) EventWhere(eventName string, predicates ...EventPredicate) EventFetcherBuilder {
+	if _, found := e.EventsAndIgnoreFields[eventName]; !found {
+		e.EventsAndIgnoreFields[eventName] = []string{}
+	}
+
+	eventPredicates := make(map[string][]EventPredicate, len(e.eventPredicates)+1)
+	for name, existing := range e.eventPredicates {
+		eventPredicates[name] = existing
+	}
+	eventPredicates[eventName] = append(slices.Clip(eventPredicates[eventName]), predicates...)
+	e.eventPredicates = eventPredicates
+	return e
+}
+
+// EventWhereJSON fetches the events of a JSON object mapping event names to predicates, see EventPredicateSpec, e.g.
+//
+//	{"A.0ae53cb6e3f42a79.FlowToken.TokensDeposited": [{"field": "amount", "min": "10.0"}]}
+func (e EventFetcherBuilder) EventWhereJSON(data []byte) EventFetcherBuilder {
+	var specs map[string][]EventPredicateSpec
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&specs); err != nil {
+		e.fail(fmt.Errorf("invalid event predicates: %w", err))
+		return e
+	}
+
+	for eventName, eventSpecs := range specs {
+		predicates := make([]EventPredicate, len(eventSpecs))
+		for i, spec := range eventSpecs {
+			predicate, err := spec.Predicate()
+			if err != nil {
+				e.fail(fmt.Errorf("invalid predicate for %s: %w", eventName, err))
+				return e
+			}
+			predicates[i] = predicate
+		}
+		e = e.EventWhere(eventName, predicates...)
+	}
+	return e
+}
+
+// matches returns true if the event matches the predicates given for its type
+func (e EventFetcherBuilder) matches(event *FormatedEvent) bool {
+	for _, predicate := range e.eventPredicates[event.Name] {
+		if !predicate(event) {
+			return false
+		}
+	}
+	return true
+}
+
+// FieldEquals matches events with a field equal to the value. Numbers are compared by value, so "1.0" equals 1.
+// Fields of nested structs and dictionaries can be selected with dots, e.g. "nft.id".
+func FieldEquals(field string, value any) EventPredicate {
+	return FieldIn(field, value)
+}
+
+// FieldIn matches events with a field equal to one of the values, see FieldEquals
+func FieldIn(field string, values ...any) EventPredicate {
+	expected := make([]string, len(values))
+	for i, value := range values {
+		expected[i] = predicateValueString(value)
+	}
+
+	return func(event *FormatedEvent) bool {
+		value, found := eventField(event, field)
+		if !found {
+			return false
+		}
+		actual := predicateValueString(value)
+		for _, e := range expected {
+			if predicateValuesEqual(actual, e) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FieldBetween matches events with a numeric field within the inclusive range. A nil bound isn't checked.
+// Bounds can be numbers, Decimal values or strings such as "10.5"; no event matches a bound that isn't a number.
+func FieldBetween(field string, lowest, highest any) EventPredicate {
+	var lowestNumber, highestNumber *big.Rat
+	valid := true
+	if lowest != nil {
+		lowestNumber, valid = parseDecimal(predicateValueString(lowest))
+	}
+	if highest != nil && valid {
+		highestNumber, valid = parseDecimal(predicateValueString(highest))
+	}
+
+	return func(event *FormatedEvent) bool {
+		if !valid {
+			return false
+		}
+		value, found := eventField(event, field)
+		if !found {
+			return false
+		}
+		number, ok := parseDecimal(predicateValueString(value))
+		if !ok {
+			return false
+		}
+		if lowestNumber != nil && number.Cmp(lowestNumber) < 0 {
+			return false
+		}
+		if highestNumber != nil && number.Cmp(highestNumber) > 0 {
+			return false
+		}
+		return true
+	}
+}
+
+// FieldAddress matches events with an address field equal to one of the addresses. Fields that don't hold
+// an address, including empty optionals, never match.
+func FieldAddress(field string, addresses ...flow.Address) EventPredicate {
+	return func(event *FormatedEvent) bool {
+		value, found := eventField(event, field)
+		if !found {
+			return false
+		}
+		actual, ok := parseAddress(predicateValueString(value))
+		if !ok {
+			return false
+		}
+		for _, address := range addresses {
+			if actual == address {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllOf matches events matching all predicates
+func AllOf(predicates ...EventPredicate) EventPredicate {
+	return func(event *FormatedEvent) bool {
+		for _, predicate := range predicates {
+			if !predicate(event) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyOf matches events matching at least one of the predicates
+func AnyOf(predicates ...EventPredicate) EventPredicate {
+	return func(event *FormatedEvent) bool {
+		for _, predicate := range predicates {
+			if predicate(event) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// EventPredicateSpec is the JSON form of an event predicate. The conditions on a field must all hold,
+// and AnyOf holds alternative predicates, e.g.
+//
+//	{"field": "amount", "min": "10.0", "max": "100.0"}
+//	{"anyOf": [{"field": "to", "addresses": ["0x01cf0e2f2f715450"]}, {"field": "id", "in": [1, 2, 3]}]}
+type EventPredicateSpec struct {
+	Field     string               `json:"field,omitempty"`
+	Equals    any                  `json:"equals,omitempty"`
+	In        []any                `json:"in,omitempty"`
+	Min       any                  `json:"min,omitempty"`
+	Max       any                  `json:"max,omitempty"`
+	Addresses []string             `json:"addresses,omitempty"`
+	AnyOf     []EventPredicateSpec `json:"anyOf,omitempty"`
+}
+
+// Predicate builds the predicate described by the spec
+func (s EventPredicateSpec) Predicate() (EventPredicate, error) {
+	var predicates []EventPredicate
+
+	hasFieldConditions := s.Equals != nil || s.In != nil || s.Min != nil || s.Max != nil || s.Addresses != nil
+	if s.Field == "" && hasFieldConditions {
+		return nil, fmt.Errorf("a field condition needs a field")
+	}
+	if s.Field != "" && !hasFieldConditions {
+		return nil, fmt.Errorf("no condition on field %s", s.Field)
+	}
+
+	if s.Equals != nil {
+		predicates = append(predicates, FieldEquals(s.Field, s.Equals))
+	}
+	if s.In != nil {
+		predicates = append(predicates, FieldIn(s.Field, s.In...))
+	}
+	if s.Min != nil || s.Max != nil {
+		for _, bound := range []any{s.Min, s.Max} {
+			if bound == nil {
+				continue
+			}
+			if _, ok := parseDecimal(predicateValueString(bound)); !ok {
+				return nil, fmt.Errorf("bound %v of field %s isn't a number", bound, s.Field)
+			}
+		}
+		predicates = append(predicates, FieldBetween(s.Field, s.Min, s.Max))
+	}
+	if s.Addresses != nil {
+		addresses := make([]flow.Address, len(s.Addresses))
+		for i, address := range s.Addresses {
+			parsed, ok := parseAddress(address)
+			if !ok {
+				return nil, fmt.Errorf("address %s of field %s isn't valid", address, s.Field)
+			}
+			addresses[i] = parsed
+		}
+		predicates = append(predicates, FieldAddress(s.Field, addresses...))
+	}
+
+	if s.AnyOf != nil {
+		alternatives := make([]EventPredicate, len(s.AnyOf))
+		for i, spec := range s.AnyOf {
+			predicate, err := spec.Predicate()
+			if err != nil {
+				return nil, err
+			}
+			alternatives[i] = predicate
+		}
+		predicates = append(predicates, AnyOf(alternatives...))
+	}
+
+	if len(predicates) == 0 {
+		return nil, fmt.Errorf("empty predicate")
+	}
+	return AllOf(predicates...), nil
+}
+
+// eventField returns the value of a field of an event, following dots into nested structs and dictionaries
+func eventField(event *FormatedEvent, path string) (any, bool) {
+	var value any = event.Fields
+	for _, name := range strings.Split(path, ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok = fields[name]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// predicateValueString formats a value the way CadenceValueToInterface formats event fields
+func predicateValueString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case flow.Address:
+		return "0x" + v.Hex()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// predicateValuesEqual compares two formatted values, as numbers if both are decimal numbers
+func predicateValuesEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	x, ok := parseDecimal(a)
+	if !ok {
+		return false
+	}
+	y, ok := parseDecimal(b)
+	if !ok {
+		return false
+	}
+	return x.Cmp(y) == 0
+}
+
+// decimalPattern matches the numbers formatted by Cadence, unlike big.Rat which also parses
+// prefixes such as 0x and fractions such as 1/2
+var decimalPattern = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?$`)
+
+// parseDecimal parses a plain decimal number such as "-12" or "10.5"
+func parseDecimal(value string) (*big.Rat, bool) {
+	if !decimalPattern.MatchString(value) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(value)
+}
+
+// parseAddress parses a hex address with an optional 0x prefix, rejecting anything else
+func parseAddress(value string) (flow.Address, bool) {
+	digits := strings.TrimPrefix(value, "0x")
+	if digits == "" || len(digits) > 2*flow.AddressLength {
+		return flow.EmptyAddress, false
+	}
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return flow.EmptyAddress, false
+	}
+	return flow.BytesToAddress(b), true
+}
@@ -0,0 +1,101 @@
+package splash_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventPredicates(t *testing.T) {
+
+	event := NewTestEvent("A.1.Token.Deposited", map[string]interface{}{
+		"amount": "100.00000000",
+		"to":     "0x01cf0e2f2f715450",
+		"id":     "42",
+		"code":   "0x10",
+		"buyer":  nil,
+		"nft": map[string]interface{}{
+			"kind": "rare",
+		},
+	})
+
+	t.Run("equality", func(t *testing.T) {
+		assert.True(t, FieldEquals("amount", "100.0")(event))
+		assert.True(t, FieldEquals("id", 42)(event))
+		assert.True(t, FieldEquals("nft.kind", "rare")(event))
+		assert.False(t, FieldEquals("id", 43)(event))
+		assert.False(t, FieldEquals("missing", "")(event))
+		assert.True(t, FieldEquals("code", "0x10")(event))
+		assert.False(t, FieldEquals("code", "16")(event), "only decimal numbers compare by value")
+	})
+
+	t.Run("set membership", func(t *testing.T) {
+		assert.True(t, FieldIn("id", 1, 42)(event))
+		assert.False(t, FieldIn("id", 1, 2)(event))
+	})
+
+	t.Run("numeric range", func(t *testing.T) {
+		lowest, err := ParseUFix64("99.5")
+		require.NoError(t, err)
+
+		assert.True(t, FieldBetween("amount", "10.0", "100.0")(event))
+		assert.True(t, FieldBetween("amount", nil, 100)(event))
+		assert.True(t, FieldBetween("amount", lowest, nil)(event))
+		assert.False(t, FieldBetween("amount", "100.00000001", nil)(event))
+		assert.False(t, FieldBetween("nft.kind", nil, nil)(event))
+		assert.False(t, FieldBetween("amount", "lots", nil)(event))
+		assert.False(t, FieldBetween("amount", "1/2", nil)(event))
+		assert.False(t, FieldBetween("code", "0", nil)(event))
+	})
+
+	t.Run("address", func(t *testing.T) {
+		assert.True(t, FieldAddress("to", flow.HexToAddress("01cf0e2f2f715450"))(event))
+		assert.False(t, FieldAddress("to", flow.HexToAddress("0x01"))(event))
+		assert.False(t, FieldAddress("buyer", flow.EmptyAddress)(event))
+		assert.False(t, FieldAddress("amount", flow.HexToAddress("0x100"))(event))
+	})
+
+	t.Run("combinations", func(t *testing.T) {
+		assert.True(t, AnyOf(FieldEquals("id", 1), FieldEquals("id", 42))(event))
+		assert.False(t, AllOf(FieldEquals("id", 42), FieldEquals("nft.kind", "common"))(event))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		spec := EventPredicateSpec{
+			AnyOf: []EventPredicateSpec{
+				{Field: "to", Addresses: []string{"0xf8d6e0586b0a20c7"}},
+				{Field: "amount", Min: "50.0", Max: "150.0"},
+			},
+		}
+		predicate, err := spec.Predicate()
+		require.NoError(t, err)
+		assert.True(t, predicate(event))
+
+		_, err = EventPredicateSpec{Field: "amount"}.Predicate()
+		assert.EqualError(t, err, "no condition on field amount")
+
+		_, err = EventPredicateSpec{Equals: "1"}.Predicate()
+		assert.EqualError(t, err, "a field condition needs a field")
+
+		_, err = EventPredicateSpec{Field: "amount", Min: "lots"}.Predicate()
+		assert.EqualError(t, err, "bound lots of field amount isn't a number")
+
+		_, err = EventPredicateSpec{Field: "amount", Max: "0x10"}.Predicate()
+		assert.EqualError(t, err, "bound 0x10 of field amount isn't a number")
+
+		_, err = EventPredicateSpec{Field: "to", Addresses: []string{"0xf8d6e0586b0a20cz"}}.Predicate()
+		assert.EqualError(t, err, "address 0xf8d6e0586b0a20cz of field to isn't valid")
+	})
+
+	t.Run("invalid JSON fails the fetcher", func(t *testing.T) {
+		g, err := NewInMemoryTestConnector("examples", false)
+		require.NoError(t, err)
+
+		_, err = g.EventFetcher().EventWhereJSON([]byte(`{"A.1.Token.Deposited": [{"field": "id"}]}`)).Run(context.Background())
+		assert.EqualError(t, err, "invalid predicate for A.1.Token.Deposited: no condition on field id")
+	})
+}
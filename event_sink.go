@@ -0,0 +1,448 @@
+package splash
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
+)
+
+// EventSink persists the events of an event fetcher
+type EventSink interface {
+	// Write writes events, sorted in the order they were emitted
+	Write(events []*FormatedEvent) error
+	// Flush makes the written events durable
+	Flush() error
+}
+
+// RunTo runs the event fetcher like Run and writes the events to the sink.
+// Progress is saved once the sink has been flushed.
+func (e EventFetcherBuilder) RunTo(ctx context.Context, sink EventSink) error {
+	events, endIndex, err := e.run(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := writeToSink(sink, events); err != nil {
+		return err
+	}
+
+	return e.saveProgress(endIndex + 1)
+}
+
+// StreamTo follows new events like Follow and writes each batch to the sink.
+// Progress is saved after each batch, once the sink has been flushed.
+func (e EventFetcherBuilder) StreamTo(ctx context.Context, sink EventSink) error {
+	return e.Follow(ctx, func(batch EventBatch) error {
+		return writeToSink(sink, batch.Events)
+	})
+}
+
+func writeToSink(sink EventSink, events []*FormatedEvent) error {
+	if err := sink.Write(events); err != nil {
+		return fmt.Errorf("could not write events: %w", err)
+	}
+	if err := sink.Flush(); err != nil {
+		return fmt.Errorf("could not flush events: %w", err)
+	}
+	return nil
+}
+
+// eventSinkColumns are the columns written by the CSV and SQLite sinks before the fields of an event
+var eventSinkColumns = []string{"block_height", "block_id", "block_time", "transaction_id", "transaction_index", "event_index"}
+
+func eventSinkMetadata(event *FormatedEvent) []any {
+	return []any{
+		int64(event.BlockHeight), //nolint:gosec
+		event.BlockID.Hex(),
+		event.Time.UTC().Format(time.RFC3339Nano),
+		event.TransactionID.Hex(),
+		event.TransactionIndex,
+		event.EventIndex,
+	}
+}
+
+// eventSinkFields returns the sorted field names of an event, which must not clash with the metadata columns
+func eventSinkFields(event *FormatedEvent) ([]string, error) {
+	names := maps.Keys(event.Fields)
+	for _, name := range names {
+		if slices.Contains(eventSinkColumns, name) {
+			return nil, fmt.Errorf("field %s of %s clashes with a metadata column", name, event.Name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// eventSinkValue formats a field value for a CSV cell or SQLite column, encoding structs and arrays as JSON
+func eventSinkValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		return string(data), err
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// JSONLSink writes events as newline-delimited JSON, one event per line
+type JSONLSink struct {
+	writer *bufio.Writer
+	file   *os.File
+}
+
+// NewJSONLSink creates a sink writing to w
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{writer: bufio.NewWriter(w)}
+}
+
+// OpenJSONLSink creates a sink appending to a file, which is created if needed
+func OpenJSONLSink(fileName string) (*JSONLSink, error) {
+	file, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLSink{writer: bufio.NewWriter(file), file: file}, nil
+}
+
+// Write writes the events, one JSON object per line
+func (s *JSONLSink) Write(events []*FormatedEvent) error {
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := s.writer.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes buffered events and syncs the file, if the sink was opened with OpenJSONLSink
+func (s *JSONLSink) Flush() error {
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	if s.file != nil {
+		return s.file.Sync()
+	}
+	return nil
+}
+
+// Close flushes the sink and closes its file, if the sink was opened with OpenJSONLSink
+func (s *JSONLSink) Close() error {
+	err := s.Flush()
+	if s.file != nil {
+		err = errors.Join(err, s.file.Close())
+	}
+	return err
+}
+
+// CSVSink writes events to a CSV file per event type, named after the type, e.g.
+// A.0ae53cb6e3f42a79.FlowToken.TokensDeposited.csv. The columns are the metadata of the events followed
+// by their fields, sorted by name. They are taken from the first event written to a new file, or from
+// the header of an existing file, which is appended to.
+type CSVSink struct {
+	Dir   string
+	files map[string]*csvSinkFile
+}
+
+type csvSinkFile struct {
+	file   *os.File
+	writer *csv.Writer
+	fields []string
+}
+
+// NewCSVSink creates a sink writing CSV files in the given directory, which is created if needed
+func NewCSVSink(dir string) *CSVSink {
+	return &CSVSink{
+		Dir:   dir,
+		files: map[string]*csvSinkFile{},
+	}
+}
+
+// Write appends a row per event to the file of its type
+func (s *CSVSink) Write(events []*FormatedEvent) error {
+	for _, event := range events {
+		f, err := s.file(event)
+		if err != nil {
+			return err
+		}
+
+		row := make([]string, 0, len(eventSinkColumns)+len(f.fields))
+		for _, value := range eventSinkMetadata(event) {
+			row = append(row, fmt.Sprint(value))
+		}
+		for _, name := range f.fields {
+			value, err := eventSinkValue(event.Fields[name])
+			if err != nil {
+				return err
+			}
+			row = append(row, value)
+		}
+		for name := range event.Fields {
+			if !slices.Contains(f.fields, name) {
+				return fmt.Errorf("field %s of %s isn't a column of %s", name, event.Name, f.file.Name())
+			}
+		}
+
+		if err := f.writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// file returns the file of the event's type, opening it and writing its header if needed
+func (s *CSVSink) file(event *FormatedEvent) (*csvSinkFile, error) {
+	if f, found := s.files[event.Name]; found {
+		return f, nil
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil { //nolint:gosec
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filepath.Join(s.Dir, event.Name+".csv"), os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o644) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	f := &csvSinkFile{file: file, writer: csv.NewWriter(file)}
+	header, err := csv.NewReader(file).Read()
+	switch {
+	case errors.Is(err, io.EOF):
+		f.fields, err = eventSinkFields(event)
+		if err == nil {
+			err = f.writer.Write(append(slices.Clone(eventSinkColumns), f.fields...))
+		}
+	case err == nil:
+		if len(header) < len(eventSinkColumns) || !slices.Equal(header[:len(eventSinkColumns)], eventSinkColumns) {
+			err = fmt.Errorf("%s wasn't written by a CSV sink", file.Name())
+		} else {
+			f.fields = header[len(eventSinkColumns):]
+		}
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	s.files[event.Name] = f
+	return f, nil
+}
+
+// Flush writes buffered rows and syncs the files
+func (s *CSVSink) Flush() error {
+	for _, f := range s.files {
+		f.writer.Flush()
+		if err := f.writer.Error(); err != nil {
+			return err
+		}
+		if err := f.file.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close flushes the sink and closes its files
+func (s *CSVSink) Close() error {
+	err := s.Flush()
+	for name, f := range s.files {
+		err = errors.Join(err, f.file.Close())
+		delete(s.files, name)
+	}
+	return err
+}
+
+// SQLiteSink writes events to a SQLite database, with a table per event type named after the type with dots
+// replaced by underscores, e.g. A_0ae53cb6e3f42a79_FlowToken_TokensDeposited. Tables have a column per
+// metadata value and per field, added when a field is first seen; structs and arrays are stored as JSON.
+// Events already in a table, identified by transaction ID and event index, are skipped, so that events
+// written again after a restart aren't duplicated. Events are committed by Flush.
+type SQLiteSink struct {
+	db      *sql.DB
+	tx      *sql.Tx
+	columns map[string][]string
+}
+
+// NewSQLiteSink creates a sink writing to an open database
+func NewSQLiteSink(db *sql.DB) *SQLiteSink {
+	return &SQLiteSink{db: db, columns: map[string][]string{}}
+}
+
+// OpenSQLiteSink opens or creates a SQLite database file and creates a sink writing to it
+func OpenSQLiteSink(fileName string) (*SQLiteSink, error) {
+	db, err := sql.Open("sqlite", fileName+"?_pragma=busy_timeout(10000)")
+	if err != nil {
+		return nil, err
+	}
+	return NewSQLiteSink(db), nil
+}
+
+// Write inserts the events in the current transaction
+func (s *SQLiteSink) Write(events []*FormatedEvent) error {
+	if s.tx == nil {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		s.tx = tx
+	}
+
+	for _, event := range events {
+		if err := s.insert(event); err != nil {
+			s.rollback()
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteSink) insert(event *FormatedEvent) error {
+	table := sqliteEventTable(event.Name)
+	fields, err := eventSinkFields(event)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureColumns(table, fields); err != nil {
+		return err
+	}
+
+	columns := append(slices.Clone(eventSinkColumns), fields...)
+	values := eventSinkMetadata(event)
+	for _, name := range fields {
+		value, err := eventSinkValue(event.Fields[name])
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = sqliteIdentifier(column)
+	}
+	query := fmt.Sprintf("INSERT OR IGNORE INTO %s (%s) VALUES (?%s)",
+		sqliteIdentifier(table), strings.Join(quoted, ", "), strings.Repeat(", ?", len(columns)-1))
+	_, err = s.tx.Exec(query, values...)
+	return err
+}
+
+// ensureColumns creates the table of an event type and adds the columns of fields it doesn't have yet
+func (s *SQLiteSink) ensureColumns(table string, fields []string) error {
+	columns, found := s.columns[table]
+	if !found {
+		_, err := s.tx.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			block_height INTEGER NOT NULL,
+			block_id TEXT NOT NULL,
+			block_time TEXT NOT NULL,
+			transaction_id TEXT NOT NULL,
+			transaction_index INTEGER NOT NULL,
+			event_index INTEGER NOT NULL,
+			PRIMARY KEY (transaction_id, event_index)
+		)`, sqliteIdentifier(table)))
+		if err != nil {
+			return err
+		}
+
+		columns, err = s.tableColumns(table)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, field := range fields {
+		if slices.Contains(columns, field) {
+			continue
+		}
+		_, err := s.tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s TEXT", sqliteIdentifier(table), sqliteIdentifier(field)))
+		if err != nil {
+			return err
+		}
+		columns = append(columns, field)
+	}
+
+	s.columns[table] = columns
+	return nil
+}
+
+func (s *SQLiteSink) tableColumns(table string) ([]string, error) {
+	rows, err := s.tx.Query(fmt.Sprintf("SELECT name FROM pragma_table_info(%s)", sqliteString(table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// Flush commits the events written since the last flush
+func (s *SQLiteSink) Flush() error {
+	if s.tx == nil {
+		return nil
+	}
+	tx := s.tx
+	s.tx = nil
+	if err := tx.Commit(); err != nil {
+		// the columns added in the transaction are gone
+		s.columns = map[string][]string{}
+		return err
+	}
+	return nil
+}
+
+// rollback discards the events written since the last flush
+func (s *SQLiteSink) rollback() {
+	_ = s.tx.Rollback()
+	s.tx = nil
+	s.columns = map[string][]string{}
+}
+
+// Close commits pending events and closes the database
+func (s *SQLiteSink) Close() error {
+	return errors.Join(s.Flush(), s.db.Close())
+}
+
+// sqliteEventTable returns the table name of an event type
+func sqliteEventTable(eventName string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, eventName)
+}
+
+func sqliteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func sqliteString(value string) string {
+	return `'` + strings.ReplaceAll(value, `'`, `''`) + `'`
+}
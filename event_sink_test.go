@@ -0,0 +1,172 @@
+package splash_test
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sinkTestEvents() []*FormatedEvent {
+	return []*FormatedEvent{
+		{
+			Name:          "A.1.Token.Deposited",
+			BlockHeight:   10,
+			BlockID:       flow.HexToID("0a"),
+			Time:          time.Unix(100, 0).UTC(),
+			TransactionID: flow.HexToID("01"),
+			EventIndex:    1,
+			Fields:        map[string]interface{}{"amount": "1.00000000", "to": "0x01"},
+		},
+		{
+			Name:          "A.1.Token.Minted",
+			BlockHeight:   10,
+			BlockID:       flow.HexToID("0a"),
+			Time:          time.Unix(100, 0).UTC(),
+			TransactionID: flow.HexToID("01"),
+			EventIndex:    2,
+			Fields:        map[string]interface{}{"amount": "1.00000000", "ids": []interface{}{"1", "2"}},
+		},
+		{
+			Name:             "A.1.Token.Deposited",
+			BlockHeight:      11,
+			BlockID:          flow.HexToID("0b"),
+			Time:             time.Unix(101, 0).UTC(),
+			TransactionID:    flow.HexToID("02"),
+			TransactionIndex: 1,
+			Fields:           map[string]interface{}{"amount": "2.00000000", "to": "0x02"},
+		},
+	}
+}
+
+func TestEventSinks(t *testing.T) {
+
+	t.Run("JSONL", func(t *testing.T) {
+		var buffer bytes.Buffer
+		sink := NewJSONLSink(&buffer)
+		require.NoError(t, sink.Write(sinkTestEvents()))
+		assert.Zero(t, buffer.Len(), "events are buffered until flushed")
+		require.NoError(t, sink.Flush())
+
+		var events []*FormatedEvent
+		scanner := bufio.NewScanner(&buffer)
+		for scanner.Scan() {
+			var event FormatedEvent
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+			events = append(events, &event)
+		}
+		assert.Equal(t, sinkTestEvents(), events)
+	})
+
+	t.Run("JSONL file is appended to", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "events.jsonl")
+		for i := 0; i < 2; i++ {
+			sink, err := OpenJSONLSink(fileName)
+			require.NoError(t, err)
+			require.NoError(t, sink.Write(sinkTestEvents()))
+			require.NoError(t, sink.Close())
+		}
+
+		data, err := os.ReadFile(fileName)
+		require.NoError(t, err)
+		assert.Equal(t, 6, bytes.Count(data, []byte("\n")))
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		dir := t.TempDir()
+		for i := 0; i < 2; i++ {
+			sink := NewCSVSink(dir)
+			require.NoError(t, sink.Write(sinkTestEvents()))
+			require.NoError(t, sink.Close())
+		}
+
+		file, err := os.Open(filepath.Join(dir, "A.1.Token.Deposited.csv"))
+		require.NoError(t, err)
+		defer file.Close()
+		rows, err := csv.NewReader(file).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+		assert.Equal(t, []string{"block_height", "block_id", "block_time", "transaction_id", "transaction_index", "event_index", "amount", "to"}, rows[0])
+		assert.Equal(t, []string{"11", flow.HexToID("0b").Hex(), "1970-01-01T00:01:41Z", flow.HexToID("02").Hex(), "1", "0", "2.00000000", "0x02"}, rows[2])
+
+		file, err = os.Open(filepath.Join(dir, "A.1.Token.Minted.csv"))
+		require.NoError(t, err)
+		defer file.Close()
+		rows, err = csv.NewReader(file).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, `["1","2"]`, rows[1][7])
+	})
+
+	t.Run("CSV rejects unknown fields", func(t *testing.T) {
+		sink := NewCSVSink(t.TempDir())
+		defer sink.Close()
+		events := sinkTestEvents()
+		events[2].Fields["memo"] = "hi"
+		err := sink.Write(events)
+		assert.ErrorContains(t, err, "field memo of A.1.Token.Deposited isn't a column of")
+	})
+
+	t.Run("SQLite", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "events.db")
+		sink, err := OpenSQLiteSink(fileName)
+		require.NoError(t, err)
+		require.NoError(t, sink.Write(sinkTestEvents()))
+		require.NoError(t, sink.Flush())
+
+		// events written again are skipped and new fields become columns
+		events := sinkTestEvents()
+		events[2].Fields["memo"] = "hi"
+		require.NoError(t, sink.Write(events))
+		events[2].TransactionID = flow.HexToID("03")
+		require.NoError(t, sink.Write(events[2:]))
+		require.NoError(t, sink.Close())
+
+		db, err := sql.Open("sqlite", fileName)
+		require.NoError(t, err)
+		defer db.Close()
+
+		var count int
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM A_1_Token_Deposited`).Scan(&count))
+		assert.Equal(t, 3, count)
+
+		var amount string
+		var memo sql.NullString
+		require.NoError(t, db.QueryRow(`SELECT amount, memo FROM A_1_Token_Deposited WHERE transaction_id = ?`, flow.HexToID("03").Hex()).Scan(&amount, &memo))
+		assert.Equal(t, "2.00000000", amount)
+		assert.Equal(t, "hi", memo.String)
+	})
+
+	t.Run("SQLite discards a failed write", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "events.db")
+		sink, err := OpenSQLiteSink(fileName)
+		require.NoError(t, err)
+		defer sink.Close()
+
+		events := sinkTestEvents()
+		events[2].Fields["block_id"] = "clash"
+		err = sink.Write(events)
+		assert.EqualError(t, err, "field block_id of A.1.Token.Deposited clashes with a metadata column")
+
+		require.NoError(t, sink.Write(sinkTestEvents()[:1]))
+		require.NoError(t, sink.Flush())
+
+		db, err := sql.Open("sqlite", fileName)
+		require.NoError(t, err)
+		defer db.Close()
+
+		var count int
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM A_1_Token_Deposited`).Scan(&count))
+		assert.Equal(t, 1, count)
+	})
+}
@@ -0,0 +1,160 @@
+package splash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EventBatch holds the events of a range of blocks, sorted by block height
+type EventBatch struct {
+	StartHeight uint64
+	EndHeight   uint64
+	Events      []*FormatedEvent
+
+	done chan struct{}
+}
+
+// Done acknowledges a batch received from Stream, which saves its progress and lets the next batch through.
+// Batches passed to Follow handlers are acknowledged when the handler returns. Calling Done again is a no-op.
+func (b EventBatch) Done() {
+	select {
+	case b.done <- struct{}{}:
+	default:
+	}
+}
+
+// PollInterval sets how long Follow and Stream wait for new blocks once they have caught up with the chain
+func (e EventFetcherBuilder) PollInterval(interval time.Duration) EventFetcherBuilder {
+	e.PollingInterval = interval
+	return e
+}
+
+// Follow fetches events continuously, polling for new blocks, and passes them to the handler one batch at a time.
+//
+// Fetching starts at the height stored in the progress store if there is one, otherwise at FromIndex, which is
+// relative to the latest block when it isn't positive. The progress is saved once the handler has returned
+// successfully, so a restarted fetcher resumes with the first batch that wasn't handled. Ranges without events
+// don't reach the handler but still count as progress. Follow returns when the context is cancelled, when the
+// handler fails or, if End or Until is set, once the end height has been handled.
+func (e EventFetcherBuilder) Follow(ctx context.Context, handler func(batch EventBatch) error) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	e, err := e.loadProgress()
+	if err != nil {
+		return err
+	}
+
+	latest, err := e.latestHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	next := e.FromIndex
+	// if we have a negative fromIndex is relative to the latest block
+	if e.FromIndex <= 0 {
+		next = int64(latest) + e.FromIndex //nolint:gosec
+	}
+	if next < 0 {
+		return fmt.Errorf("FromIndex is negative")
+	}
+	fromIndex := uint64(next)
+
+	endIndex, bounded := e.EndIndex, !e.EndAtCurrentHeight && e.EndIndex > 0
+
+	maxBlocks := e.EventBatchSize * uint64(max(e.NumberOfWorkers, 1)) //nolint:gosec
+	if maxBlocks == 0 {
+		maxBlocks = 1
+	}
+
+	for {
+		if bounded && fromIndex > endIndex {
+			return nil
+		}
+
+		toIndex := min(latest, fromIndex+maxBlocks-1)
+		if bounded {
+			toIndex = min(toIndex, endIndex)
+		}
+
+		if fromIndex <= toIndex {
+			e.Connector.Logger.Debug(fmt.Sprintf("Fetching events from %d to %d", fromIndex, toIndex))
+			events, err := e.fetch(ctx, fromIndex, toIndex)
+			if err != nil {
+				return err
+			}
+
+			if len(events) > 0 {
+				if err := handler(EventBatch{StartHeight: fromIndex, EndHeight: toIndex, Events: events}); err != nil {
+					return err
+				}
+			}
+
+			if err := e.saveProgress(toIndex + 1); err != nil {
+				return err
+			}
+			fromIndex = toIndex + 1
+
+			if fromIndex <= latest {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(e.PollingInterval):
+		}
+
+		latest, err = e.latestHeight(ctx)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Stream runs Follow in the background and sends the batches on the returned channel. Each batch must be
+// acknowledged with Done once it has been handled; its progress is saved then and the next batch is sent, so
+// a restarted stream resumes with the first batch that wasn't acknowledged. Both channels are closed when
+// streaming stops; the error channel receives the error that stopped it, unless the context was cancelled.
+func (e EventFetcherBuilder) Stream(ctx context.Context) (<-chan EventBatch, <-chan error) {
+	batches := make(chan EventBatch)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(batches)
+
+		err := e.Follow(ctx, func(batch EventBatch) error {
+			done := make(chan struct{}, 1)
+			batch.done = done
+
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				// a batch acknowledged just before cancellation still counts as handled
+				select {
+				case <-done:
+					return nil
+				default:
+					return ctx.Err()
+				}
+			}
+		})
+		if err != nil && !errors.Is(err, ctx.Err()) {
+			errs <- err
+		}
+	}()
+
+	return batches, errs
+}
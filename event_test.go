@@ -1,9 +1,12 @@
 package splash_test
 
 import (
+	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/onflow/flow-go-sdk"
 	. "github.com/piprate/splash"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -72,4 +75,30 @@ func TestEvent(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "foo: is a directory")
 	})
+
+	t.Run("JSON leaves out unknown metadata", func(t *testing.T) {
+		ev := NewTestEvent("A.1.Foo.Bar", map[string]interface{}{"baz": "1"})
+		assert.JSONEq(t, `{"name":"A.1.Foo.Bar","time":"1970-01-01T00:00:00Z","fields":{"baz":"1"}}`, ev.String())
+	})
+
+	t.Run("JSON round trip with metadata", func(t *testing.T) {
+		ev := &FormatedEvent{
+			Name:             "A.1.Foo.Bar",
+			BlockHeight:      12,
+			BlockID:          flow.HexToID("01"),
+			Time:             time.Unix(100, 0).UTC(),
+			TransactionID:    flow.HexToID("02"),
+			TransactionIndex: 0,
+			EventIndex:       3,
+			Fields:           map[string]interface{}{"baz": "1"},
+		}
+
+		data, err := json.Marshal(ev)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"transactionIndex":0`)
+
+		var decoded FormatedEvent
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, *ev, decoded)
+	})
 }
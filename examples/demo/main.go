@@ -4,10 +4,14 @@ import (
 	"context"
 	"log"
 
-	"github.com/onflow/cadence"
 	"github.com/piprate/splash"
 )
 
+// Foo mirrors the Foo struct of the Debug contract
+type Foo struct {
+	Bar string `cadence:"bar"`
+}
+
 func main() {
 
 	//This method starts an in memory flow emulator
@@ -29,17 +33,7 @@ func main() {
 		return
 	}
 
-	structValue := cadence.NewStruct(
-		[]cadence.Value{cadence.String("baz")},
-	)
-	structValue.StructType = cadence.NewStructType(nil,
-		"A.f8d6e0586b0a20c7.Debug.Foo",
-		[]cadence.Field{{
-			Identifier: "bar",
-			Type:       cadence.StringType,
-		}},
-		nil)
-
+	//struct arguments are built from go values. The type ID of Debug.Foo is resolved from the address Debug is deployed to on the current network
 	g.Transaction(`
 import Debug from "../contracts/Debug.cdc"
 
@@ -47,7 +41,7 @@ transaction(value:Debug.Foo) {
   prepare(acct: &Account) {
 	Debug.log(value.bar)
  }
-}`).SignProposeAndPayAs("first").Argument(structValue).RunPrintEventsFull(ctx)
+}`).SignProposeAndPayAs("first").StructArgument("Debug.Foo", Foo{Bar: "baz"}).RunPrintEventsFull(ctx)
 
 	//this first transaction will setup a NFTCollection for the user "emulator-first".
 	// transactions are looked up in the `transactions` folder.
@@ -2,11 +2,15 @@ package examples_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/onflow/flowkit/v2"
 	"github.com/piprate/splash"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -80,6 +84,52 @@ func TestEvents(t *testing.T) {
 		assert.True(t, ev[0].BlockHeight < ev[1].BlockHeight)
 	})
 
+	t.Run("Fetch events with transaction metadata", func(t *testing.T) {
+		ctx := context.Background()
+		g, err := splash.NewInMemoryTestConnector(".", false)
+		require.NoError(t, err)
+		res, err := g.TransactionFromFile("mint_tokens").
+			SignProposeAndPayAsService().
+			AccountArgument("zero").
+			UFix64Argument("100.0").
+			RunWithResultE(ctx)
+		require.NoError(t, err)
+
+		ev, err := g.EventFetcher().Last(1).
+			Event("A.0ae53cb6e3f42a79.FlowToken.TokensMinted").
+			Event("A.0ae53cb6e3f42a79.FlowToken.TokensDeposited").
+			Run(ctx)
+		require.NoError(t, err)
+		require.Len(t, ev, 2)
+		for _, e := range ev {
+			assert.Equal(t, res.TransactionID, e.TransactionID)
+			assert.Equal(t, res.BlockID, e.BlockID)
+		}
+		// events are sorted in the order they were emitted
+		assert.Equal(t, "A.0ae53cb6e3f42a79.FlowToken.TokensMinted", ev[0].Name)
+		assert.Less(t, ev[0].EventIndex, ev[1].EventIndex)
+	})
+
+	t.Run("Test results carry block metadata", func(t *testing.T) {
+		g, err := splash.NewInMemoryTestConnector(".", false)
+		require.NoError(t, err)
+		result := g.TransactionFromFile("mint_tokens").
+			SignProposeAndPayAsService().
+			AccountArgument("zero").
+			UFix64Argument("100.0").
+			Test(t).
+			AssertSuccess().
+			AssertEmitEvent(splash.NewTestEvent("A.0ae53cb6e3f42a79.FlowToken.TokensMinted", map[string]interface{}{"amount": "100.00000000"}))
+
+		latest, err := g.Services.GetBlock(context.Background(), flowkit.LatestBlockQuery)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Events)
+		for _, e := range result.Events {
+			assert.Equal(t, latest.ID, e.BlockID)
+			assert.Equal(t, latest.Height, e.BlockHeight)
+		}
+	})
+
 	t.Run("Fetch last write progress file", func(t *testing.T) {
 		ctx := context.Background()
 		g, err := splash.NewInMemoryTestConnector(".", false)
@@ -94,6 +144,7 @@ func TestEvents(t *testing.T) {
 
 		ev, err := g.EventFetcher().Event("A.0ae53cb6e3f42a79.FlowToken.TokensMinted").TrackProgressIn("progress").Run(ctx)
 		defer os.Remove("progress")
+		defer os.Remove("progress.lock")
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(ev))
 	})
@@ -129,8 +180,202 @@ func TestEvents(t *testing.T) {
 
 		ev, err := g.EventFetcher().Event("A.0ae53cb6e3f42a79.FlowToken.TokensMinted").TrackProgressIn("progress").Run(ctx)
 		defer os.Remove("progress")
+		defer os.Remove("progress.lock")
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(ev))
 	})
 
 }
+
+func TestEventStream(t *testing.T) {
+	const tokensMinted = "A.0ae53cb6e3f42a79.FlowToken.TokensMinted"
+
+	mint := func(t *testing.T, g *splash.Connector) uint64 {
+		t.Helper()
+		res, err := g.TransactionFromFile("mint_tokens").
+			SignProposeAndPayAsService().
+			AccountArgument("zero").
+			UFix64Argument("100.0").
+			RunWithResultE(context.Background())
+		require.NoError(t, err)
+		return res.BlockHeight
+	}
+
+	t.Run("Follow until end height", func(t *testing.T) {
+		ctx := context.Background()
+		g, err := splash.NewInMemoryTestConnector(".", false)
+		require.NoError(t, err)
+		first := mint(t, g)
+		second := mint(t, g)
+
+		var batches []splash.EventBatch
+		err = g.EventFetcher().Event(tokensMinted).From(1).Until(second).BatchSize(1).Workers(1).
+			Follow(ctx, func(batch splash.EventBatch) error {
+				batches = append(batches, batch)
+				return nil
+			})
+		require.NoError(t, err)
+		require.Len(t, batches, 2)
+		assert.Equal(t, first, batches[0].Events[0].BlockHeight)
+		assert.Equal(t, second, batches[1].Events[0].BlockHeight)
+	})
+
+	t.Run("Stream new events and resume from progress file", func(t *testing.T) {
+		progressFile := filepath.Join(t.TempDir(), "progress")
+		require.NoError(t, splash.WriteProgressToFile(progressFile, 1))
+
+		g, err := splash.NewInMemoryTestConnector(".", false)
+		require.NoError(t, err)
+		first := mint(t, g)
+
+		fetcher := g.EventFetcher().Event(tokensMinted).TrackProgressIn(progressFile).PollInterval(10 * time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		batches, errs := fetcher.Stream(ctx)
+
+		batch := <-batches
+		require.Len(t, batch.Events, 1)
+		assert.Equal(t, first, batch.Events[0].BlockHeight)
+		batch.Done()
+
+		second := mint(t, g)
+		batch = <-batches
+		require.Len(t, batch.Events, 1)
+		assert.Equal(t, second, batch.Events[0].BlockHeight)
+		assert.Greater(t, batch.Events[0].BlockHeight, first)
+		batch.Done()
+
+		cancel()
+		for range batches {
+		}
+		assert.NoError(t, <-errs)
+
+		progress, err := splash.ReadProgressFromFile(progressFile)
+		require.NoError(t, err)
+		assert.Equal(t, int64(second+1), progress) //nolint:gosec
+
+		third := mint(t, g)
+
+		// a batch that isn't acknowledged is delivered again
+		ctx, cancel = context.WithCancel(context.Background())
+		batches, errs = fetcher.Stream(ctx)
+		batch = <-batches
+		require.Len(t, batch.Events, 1)
+		assert.Equal(t, third, batch.Events[0].BlockHeight)
+		cancel()
+		for range batches {
+		}
+		assert.NoError(t, <-errs)
+
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		var events []*splash.FormatedEvent
+		err = fetcher.Follow(ctx, func(batch splash.EventBatch) error {
+			events = append(events, batch.Events...)
+			cancel()
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		require.Len(t, events, 1)
+		assert.Equal(t, third, events[0].BlockHeight)
+	})
+}
+
+func TestEventPredicates(t *testing.T) {
+	const tokensDeposited = "A.0ae53cb6e3f42a79.FlowToken.TokensDeposited"
+
+	ctx := context.Background()
+	g, err := splash.NewInMemoryTestConnector(".", false)
+	require.NoError(t, err)
+	err = g.CreateAccounts(ctx, "emulator-account").InitializeContractsE(ctx)
+	require.NoError(t, err)
+
+	mint := func(account, amount string) {
+		g.TransactionFromFile("mint_tokens").
+			SignProposeAndPayAsService().
+			AccountArgument(account).
+			UFix64Argument(amount).
+			Test(t).
+			AssertSuccess()
+	}
+	mint("first", "10.0")
+	mint("second", "20.0")
+	mint("first", "30.0")
+
+	t.Run("Filter by address and amount", func(t *testing.T) {
+		ev, err := g.EventFetcher().Last(3).
+			EventWhere(tokensDeposited, splash.FieldAddress("to", g.Account("first").Address)).
+			EventWhere(tokensDeposited, splash.FieldBetween("amount", "20.0", nil)).
+			Run(ctx)
+		require.NoError(t, err)
+		require.Len(t, ev, 1)
+		assert.Equal(t, "30.00000000", ev[0].Fields["amount"])
+	})
+
+	t.Run("Filter with JSON predicates", func(t *testing.T) {
+		ev, err := g.EventFetcher().Last(3).
+			EventWhereJSON([]byte(`{"`+tokensDeposited+`": [{"field": "amount", "in": [10, "20.0"]}]}`)).
+			EventIgnoringFields(tokensDeposited, []string{"balanceAfter"}).
+			Run(ctx)
+		require.NoError(t, err)
+		require.Len(t, ev, 2)
+		assert.Equal(t, "10.00000000", ev[0].Fields["amount"])
+		assert.Equal(t, "20.00000000", ev[1].Fields["amount"])
+	})
+}
+
+type failingSink struct{}
+
+func (failingSink) Write([]*splash.FormatedEvent) error { return nil }
+func (failingSink) Flush() error                        { return errors.New("disk full") }
+
+func TestEventSinks(t *testing.T) {
+	const tokensMinted = "A.0ae53cb6e3f42a79.FlowToken.TokensMinted"
+
+	ctx := context.Background()
+	g, err := splash.NewInMemoryTestConnector(".", false)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		g.TransactionFromFile("mint_tokens").
+			SignProposeAndPayAsService().
+			AccountArgument("zero").
+			UFix64Argument("100.0").
+			Test(t).
+			AssertSuccess()
+	}
+
+	dbFile := filepath.Join(t.TempDir(), "events.db")
+	progress, err := splash.OpenSQLiteProgressStore(dbFile)
+	require.NoError(t, err)
+	defer progress.Close()
+	require.NoError(t, progress.Save("minted", 1))
+
+	t.Run("Progress isn't saved when the sink fails", func(t *testing.T) {
+		err := g.EventFetcher().Event(tokensMinted).TrackProgressWith(progress, "minted").
+			StreamTo(ctx, failingSink{})
+		assert.EqualError(t, err, "could not flush events: disk full")
+
+		height, _, err := progress.Load("minted")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), height)
+	})
+
+	t.Run("Run to a SQLite sink", func(t *testing.T) {
+		sink, err := splash.OpenSQLiteSink(dbFile)
+		require.NoError(t, err)
+		err = g.EventFetcher().Event(tokensMinted).TrackProgressWith(progress, "minted").RunTo(ctx, sink)
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+
+		db, err := sql.Open("sqlite", dbFile)
+		require.NoError(t, err)
+		defer db.Close()
+		var count int
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM A_0ae53cb6e3f42a79_FlowToken_TokensMinted WHERE amount = '100.00000000'`).Scan(&count))
+		assert.Equal(t, 2, count)
+
+		height, _, err := progress.Load("minted")
+		require.NoError(t, err)
+		assert.Greater(t, height, uint64(1))
+	})
+}
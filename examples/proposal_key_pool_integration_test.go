@@ -0,0 +1,174 @@
+package examples_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/piprate/splash"
+)
+
+func TestProposalKeyPool(t *testing.T) {
+	// concurrent transactions may reference a block that is several blocks old by the time they are sent
+	g, err := splash.NewInMemoryTestConnector(".", false, emulator.WithTransactionExpiry(600))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = g.CreateAccounts(ctx, "emulator-account").InitializeContractsE(ctx)
+	require.NoError(t, err)
+
+	pk, err := g.Account("first").Key.PrivateKey()
+	require.NoError(t, err)
+
+	otherKey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, make([]byte, crypto.MinSeedLength))
+	require.NoError(t, err)
+
+	addKeys := `
+transaction(publicKey: String, count: Int) {
+  prepare(signer: auth(AddKey) &Account) {
+    let key = PublicKey(publicKey: publicKey.decodeHex(), signatureAlgorithm: SignatureAlgorithm.ECDSA_P256)
+    var i = 0
+    while i < count {
+      signer.keys.add(publicKey: key, hashAlgorithm: HashAlgorithm.SHA3_256, weight: 1000.0)
+      i = i + 1
+    }
+  }
+}`
+
+	// add 4 more copies of the account's key, so that it has 5 proposal keys,
+	// and a key the account's signer can't sign with, which mustn't be pooled
+	g.Transaction(addKeys).
+		SignProposeAndPayAs("first").
+		StringArgument(strings.TrimPrefix((*pk).PublicKey().String(), "0x")).
+		IntArgument(4).
+		Test(t).
+		AssertSuccess()
+	g.Transaction(addKeys).
+		SignProposeAndPayAs("first").
+		StringArgument(strings.TrimPrefix(otherKey.PublicKey().String(), "0x")).
+		IntArgument(1).
+		Test(t).
+		AssertSuccess()
+
+	t.Run("Unknown key index", func(t *testing.T) {
+		_, err := g.UseProposalKeyPool(ctx, "first", splash.RoundRobin, 0, 42)
+		assert.Error(t, err)
+	})
+
+	for _, strategy := range []splash.KeySelectionStrategy{splash.RoundRobin, splash.LeastRecentlyUsed} {
+		pool, err := g.UseProposalKeyPool(ctx, "first", strategy)
+		require.NoError(t, err)
+		require.Equal(t, 5, pool.Size())
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := g.Transaction(`
+transaction {
+  prepare(acct: &Account) {}
+}`).SignProposeAndPayAs("first").RunE(ctx)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+	}
+
+	t.Run("Re-sync after sequence number mismatch", func(t *testing.T) {
+		pool, err := g.UseProposalKeyPool(ctx, "second", splash.RoundRobin, 0)
+		require.NoError(t, err)
+
+		lease, err := pool.Acquire(ctx)
+		require.NoError(t, err)
+		lease.Release(true, nil) // pretend a transaction was included, the local sequence number is now ahead
+
+		res, err := g.Transaction(`
+transaction {
+  prepare(acct: &Account) {}
+}`).SignProposeAndPayAs("second").RunWithResultE(ctx)
+		require.Error(t, err)
+		assert.Equal(t, 1007, res.ErrorCode)
+
+		_, err = g.Transaction(`
+transaction {
+  prepare(acct: &Account) {}
+}`).SignProposeAndPayAs("second").RunE(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Key is returned when the handle is dropped", func(t *testing.T) {
+		pool, err := g.UseProposalKeyPool(ctx, "second", splash.RoundRobin, 0)
+		require.NoError(t, err)
+
+		sendCtx, cancel := context.WithCancel(ctx)
+		_, err = g.Transaction(`
+transaction {
+  prepare(acct: &Account) {}
+}`).SignProposeAndPayAs("second").Send(sendCtx)
+		cancel()
+		require.NoError(t, err)
+
+		acquireCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		lease, err := pool.Acquire(acquireCtx)
+		require.NoError(t, err)
+		lease.Release(false, nil)
+	})
+
+	t.Run("Failures mentioning sequence numbers aren't retried", func(t *testing.T) {
+		_, err := g.UseProposalKeyPool(ctx, "second", splash.RoundRobin, 0)
+		require.NoError(t, err)
+
+		sequenceNumber := func() uint64 {
+			account, err := g.Services.GetAccount(ctx, g.Account("second").Address)
+			require.NoError(t, err)
+			return account.Keys[0].SequenceNumber
+		}
+		before := sequenceNumber()
+
+		policy := splash.DefaultRetryPolicy()
+		policy.InitialBackoff = time.Millisecond
+
+		_, err = g.Transaction(`
+transaction {
+  prepare(acct: &Account) {
+    panic("bad sequence number")
+  }
+}`).SignProposeAndPayAs("second").Retry(policy).RunWithResultE(ctx)
+		require.ErrorContains(t, err, "bad sequence number")
+		assert.Equal(t, before+1, sequenceNumber(), "the transaction must be sent once")
+	})
+
+	t.Run("Retry after sequence number mismatch", func(t *testing.T) {
+		pool, err := g.UseProposalKeyPool(ctx, "second", splash.RoundRobin, 0)
+		require.NoError(t, err)
+
+		lease, err := pool.Acquire(ctx)
+		require.NoError(t, err)
+		lease.Release(true, nil)
+
+		policy := splash.DefaultRetryPolicy()
+		policy.InitialBackoff = time.Millisecond
+
+		res, err := g.Transaction(`
+transaction {
+  prepare(acct: &Account) {}
+}`).SignProposeAndPayAs("second").Retry(policy).RunWithResultE(ctx)
+		require.NoError(t, err)
+		assert.True(t, res.Succeeded())
+	})
+}
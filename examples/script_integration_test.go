@@ -2,6 +2,7 @@ package examples_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/piprate/splash"
@@ -26,6 +27,11 @@ func TestScript(t *testing.T) {
 		assert.Equal(t, "0x179b6b1cb6755e31", value)
 	})
 
+	t.Run("Go values as arguments", func(t *testing.T) {
+		value := g.ScriptFromFile("test").Args("0x01cf0e2f2f715450").RunReturnsInterface(ctx)
+		assert.Equal(t, "0x01cf0e2f2f715450", value)
+	})
+
 	t.Run("Script should report failure", func(t *testing.T) {
 		value, err := g.Script("asdf").RunReturns(ctx)
 		assert.Error(t, err)
@@ -35,3 +41,60 @@ func TestScript(t *testing.T) {
 	})
 
 }
+
+func TestScriptAtBlock(t *testing.T) {
+	ctx := context.Background()
+	g, err := splash.NewInMemoryTestConnector(".", false)
+	require.NoError(t, err)
+
+	err = g.CreateAccounts(ctx, "emulator-account").InitializeContractsE(ctx)
+	require.NoError(t, err)
+
+	balance := g.Script(`
+import FlowToken from "../contracts/FlowToken.cdc"
+
+access(all) fun main(account: Address): UFix64 {
+  return getAccount(account).balance
+}`).AccountArgument("first")
+
+	before, err := balance.RunWithResult(ctx)
+	require.NoError(t, err)
+
+	g.TransactionFromFile("mint_tokens").
+		SignProposeAndPayAsService().
+		AccountArgument("first").
+		UFix64Argument("10.0").
+		Test(t).
+		AssertSuccess()
+
+	after, err := balance.RunWithResult(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, after.BlockHeight, before.BlockHeight)
+	assert.NotEqual(t, before.Value, after.Value)
+
+	t.Run("At height", func(t *testing.T) {
+		value, err := balance.AtHeight(before.BlockHeight).RunReturns(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, before.Value, value)
+
+		result, err := g.Script(`
+access(all) fun main(): UInt64 {
+  return getCurrentBlock().height
+}`).AtHeight(before.BlockHeight).RunWithResult(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, before.BlockHeight, result.BlockHeight)
+		assert.Equal(t, before.BlockID, result.BlockID)
+		assert.Equal(t, fmt.Sprint(before.BlockHeight), result.Value.String())
+	})
+
+	t.Run("At block ID", func(t *testing.T) {
+		result, err := balance.AtBlockID(before.BlockID).RunWithResult(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, before, result)
+	})
+
+	t.Run("Fail on unknown blocks", func(t *testing.T) {
+		_, err := balance.NoRetry().AtHeight(after.BlockHeight + 100).RunWithResult(ctx)
+		assert.Error(t, err)
+	})
+}
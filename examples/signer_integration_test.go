@@ -0,0 +1,80 @@
+package examples_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/piprate/splash"
+)
+
+func TestCustomSigners(t *testing.T) {
+	g, err := splash.NewInMemoryTestConnector(".", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = g.CreateAccounts(ctx, "emulator-account").InitializeContractsE(ctx)
+	require.NoError(t, err)
+
+	first := g.Account("first")
+	firstKey, err := first.Key.PrivateKey()
+	require.NoError(t, err)
+
+	second := g.Account("second")
+	secondKey, err := second.Key.PrivateKey()
+	require.NoError(t, err)
+
+	// the key of the first account is kept in an encrypted keystore
+	keystoreFile := filepath.Join(t.TempDir(), "first.json")
+	require.NoError(t, splash.WriteKeystoreFile(keystoreFile, "passphrase", first.Address, first.Key.Index(), *firstKey, first.Key.HashAlgo()))
+	keystoreSigner, err := splash.NewKeystoreSigner(keystoreFile, "passphrase")
+	require.NoError(t, err)
+
+	// the key of the second account is held by a remote signing service
+	secondSigner, err := splash.NewInMemorySigner(second.Address, second.Key.Index(), *secondKey, second.Key.HashAlgo())
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req splash.RemoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		message, _ := hex.DecodeString(req.Message)
+		signature, err := secondSigner.Sign(message)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(splash.RemoteSignResponse{Signature: hex.EncodeToString(signature)})
+	}))
+	defer server.Close()
+	remoteSigner := splash.NewRemoteSigner(server.URL, second.Address, second.Key.Index())
+
+	t.Run("Propose and pay with a keystore signer", func(t *testing.T) {
+		g.Transaction(`
+transaction {
+  prepare(acct: &Account) {}
+}`).
+			SignProposeAndPayWith(keystoreSigner).
+			Test(t).
+			AssertSuccess()
+	})
+
+	t.Run("Mix account, keystore and remote signers", func(t *testing.T) {
+		g.Transaction(`
+transaction {
+  prepare(first: &Account, second: &Account) {}
+}`).
+			SignAndProposeWith(keystoreSigner).
+			PayloadSignerWith(remoteSigner).
+			PayAs("account").
+			Test(t).
+			AssertSuccess()
+	})
+}
@@ -7,6 +7,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flowkit/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -65,6 +68,16 @@ func TestTransaction(t *testing.T) {
 
 	})
 
+	t.Run("Mint tokens with named arguments", func(t *testing.T) {
+		g.TransactionFromFile("mint_tokens").
+			SignProposeAndPayAsService().
+			NamedArgument("amount", splash.UFix64FromFloat64(10)).
+			NamedArgument("recipient", cadence.NewAddress(g.Account("first").Address)).
+			Test(t).
+			AssertSuccess().
+			AssertEmitEvent(splash.NewTestEvent("A.0ae53cb6e3f42a79.FlowToken.TokensMinted", map[string]interface{}{"amount": "10.00000000"}))
+	})
+
 	t.Run("Inline transaction with debug log", func(t *testing.T) {
 		g.Transaction(`
 import Debug from "../contracts/Debug.cdc"
@@ -82,6 +95,21 @@ transaction(message:String) {
 
 	})
 
+	t.Run("Struct argument", func(t *testing.T) {
+		g.Transaction(`
+import Debug from "../contracts/Debug.cdc"
+transaction(value: Debug.Foo) {
+  prepare(acct: &Account) {
+	Debug.log(value.bar)
+ }
+}`).
+			SignProposeAndPayAs("first").
+			StructArgument("Debug.Foo", map[string]interface{}{"bar": "baz"}).
+			Test(t).
+			AssertSuccess().
+			AssertDebugLog("baz")
+	})
+
 	t.Run("Raw account argument", func(t *testing.T) {
 		g.Transaction(`
 import Debug from "../contracts/Debug.cdc"
@@ -111,6 +139,63 @@ transaction(user:Address) {
 			AssertFailure("has no member `toStrig`") //assert failure with an error message. uses contains so you do not need to write entire message
 	})
 
+	t.Run("Run with result", func(t *testing.T) {
+		res, err := g.TransactionFromFile("mint_tokens").
+			SignProposeAndPayAsService().
+			AccountArgument("first").
+			UFix64Argument("100.0").
+			RunWithResultE(ctx)
+		require.NoError(t, err)
+		assert.True(t, res.Succeeded())
+		assert.NotEqual(t, flow.EmptyID, res.TransactionID)
+		assert.NotEqual(t, flow.EmptyID, res.BlockID)
+		assert.NotZero(t, res.BlockHeight)
+		assert.Equal(t, flow.TransactionStatusSealed, res.Status)
+		assert.Equal(t, 0, res.ErrorCode)
+		assert.Len(t, res.Events, 4)
+		require.Len(t, res.FormatedEvents, 4)
+		assert.Equal(t, res.BlockHeight, res.FormatedEvents[0].BlockHeight)
+
+		block, err := g.Services.GetBlock(ctx, flowkit.BlockQuery{ID: &res.BlockID})
+		require.NoError(t, err)
+		assert.Equal(t, block.Timestamp, res.FormatedEvents[0].Time)
+	})
+
+	t.Run("Send and wait for execution", func(t *testing.T) {
+		handle, err := g.TransactionFromFile("mint_tokens").
+			SignProposeAndPayAsService().
+			AccountArgument("first").
+			UFix64Argument("100.0").
+			Send(ctx)
+		require.NoError(t, err)
+		assert.NotEqual(t, flow.EmptyID, handle.ID)
+
+		res, err := handle.Wait(ctx, flow.TransactionStatusExecuted)
+		require.NoError(t, err)
+		assert.Equal(t, handle.ID, res.TransactionID)
+		assert.GreaterOrEqual(t, res.Status, flow.TransactionStatusExecuted)
+		assert.Len(t, res.Events, 4)
+
+		_, err = handle.Wait(ctx, flow.TransactionStatusExpired)
+		assert.Error(t, err)
+	})
+
+	t.Run("Run with result of failed transaction", func(t *testing.T) {
+		res, err := g.Transaction(`
+transaction {
+  prepare(acct: &Account) {
+	panic("boom")
+  }
+}`).
+			SignProposeAndPayAs("first").
+			RunWithResultE(ctx)
+		require.Error(t, err)
+		require.NotNil(t, res)
+		assert.False(t, res.Succeeded())
+		assert.Equal(t, 1101, res.ErrorCode)
+		assert.NotEqual(t, flow.EmptyID, res.TransactionID)
+	})
+
 	t.Run("Assert print events", func(t *testing.T) {
 		ctx := context.Background()
 		var str bytes.Buffer
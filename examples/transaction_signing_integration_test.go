@@ -0,0 +1,79 @@
+package examples_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/piprate/splash"
+)
+
+func TestMultiPartySigning(t *testing.T) {
+	g, err := splash.NewInMemoryTestConnector(".", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = g.CreateAccounts(ctx, "emulator-account").InitializeContractsE(ctx)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+
+	// the coordinator builds the transaction and exports it for the signers
+	tx, err := g.Transaction(`
+transaction(amount: Int) {
+  prepare(first: &Account, second: &Account) {}
+}`).
+		SignAndProposeAs("first").
+		PayloadSigner("second").
+		PayAs("account").
+		IntArgument(42).
+		BuildUnsigned(ctx)
+	require.NoError(t, err)
+	require.NoError(t, splash.WriteTransactionFile(filepath.Join(dir, "unsigned.rlp"), tx))
+
+	description := splash.DescribeTransaction(tx)
+	assert.Equal(t, g.Account("first").Address.HexWithPrefix(), description.Proposer.Address)
+	assert.Equal(t, g.Account("account").Address.HexWithPrefix(), description.Payer)
+	assert.Len(t, description.Authorizers, 2)
+	assert.Len(t, description.CodeHash, 64)
+	assert.Empty(t, description.PayloadSignatures)
+	descriptionJSON, err := json.Marshal(description)
+	require.NoError(t, err)
+	assert.Contains(t, string(descriptionJSON), `"arguments":[{"value":"42","type":"Int"}]`)
+
+	// every authorizer signs its own copy
+	for _, signer := range []string{"first", "second"} {
+		partial, err := splash.ReadTransactionFile(filepath.Join(dir, "unsigned.rlp"))
+		require.NoError(t, err)
+		require.NoError(t, g.SignTransaction(partial, signer))
+		require.NoError(t, splash.WriteTransactionFile(filepath.Join(dir, signer+".rlp"), partial))
+	}
+
+	// the payer collects the signatures, signs the envelope and submits the transaction
+	for _, signer := range []string{"first", "second", "first"} {
+		require.NoError(t, splash.AddSignaturesFromFile(tx, filepath.Join(dir, signer+".rlp")))
+	}
+	assert.Len(t, tx.PayloadSignatures, 2)
+	require.NoError(t, g.SignTransaction(tx, "account"))
+
+	handle, err := g.SubmitSigned(ctx, tx)
+	require.NoError(t, err)
+	result, err := handle.Wait(ctx, flow.TransactionStatusSealed)
+	require.NoError(t, err)
+	assert.Equal(t, tx.ID(), result.TransactionID)
+
+	t.Run("Reject signatures for a different payload", func(t *testing.T) {
+		other, err := g.Transaction(`
+transaction {
+  prepare(acct: &Account) {}
+}`).SignProposeAndPayAs("first").BuildUnsigned(ctx)
+		require.NoError(t, err)
+
+		assert.Error(t, splash.AddSignaturesFromFile(other, filepath.Join(dir, "second.rlp")))
+	})
+}
@@ -0,0 +1,160 @@
+package splash
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreKDF    = "scrypt"
+	keystoreCipher = "aes-256-gcm"
+	keystoreKeyLen = 32
+)
+
+type (
+	// keystoreFile is the JSON layout of an encrypted keystore file
+	keystoreFile struct {
+		Address  string         `json:"address"`
+		KeyIndex uint32         `json:"keyIndex"`
+		SigAlgo  string         `json:"sigAlgo"`
+		HashAlgo string         `json:"hashAlgo"`
+		Crypto   keystoreCrypto `json:"crypto"`
+	}
+
+	keystoreCrypto struct {
+		KDF        string            `json:"kdf"`
+		KDFParams  keystoreKDFParams `json:"kdfparams"`
+		Cipher     string            `json:"cipher"`
+		Nonce      string            `json:"nonce"`
+		Ciphertext string            `json:"ciphertext"`
+	}
+
+	keystoreKDFParams struct {
+		N    int    `json:"n"`
+		R    int    `json:"r"`
+		P    int    `json:"p"`
+		Salt string `json:"salt"`
+	}
+)
+
+// WriteKeystoreFile encrypts the private key with the passphrase and writes it to a keystore file.
+// The key is encrypted with AES-256-GCM using a key derived from the passphrase with scrypt.
+func WriteKeystoreFile(fileName, passphrase string, address flow.Address, keyIndex uint32, privateKey crypto.PrivateKey, hashAlgo crypto.HashAlgorithm) error {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	file := keystoreFile{
+		Address:  address.HexWithPrefix(),
+		KeyIndex: keyIndex,
+		SigAlgo:  privateKey.Algorithm().String(),
+		HashAlgo: hashAlgo.String(),
+		Crypto: keystoreCrypto{
+			KDF:       keystoreKDF,
+			KDFParams: keystoreKDFParams{N: 1 << 15, R: 8, P: 1, Salt: hex.EncodeToString(salt)},
+			Cipher:    keystoreCipher,
+		},
+	}
+
+	aead, err := file.aead(passphrase)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	file.Crypto.Nonce = hex.EncodeToString(nonce)
+	file.Crypto.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, privateKey.Encode(), file.additionalData()))
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(fileName, data, 0o600)
+}
+
+// NewKeystoreSigner decrypts the key in a keystore file written by WriteKeystoreFile and returns a signer for it
+func NewKeystoreSigner(fileName, passphrase string) (*InMemorySigner, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keystoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid keystore file %s: %w", fileName, err)
+	}
+	if file.Crypto.KDF != keystoreKDF || file.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unsupported keystore encryption %s/%s", file.Crypto.KDF, file.Crypto.Cipher)
+	}
+
+	aead, err := file.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := hex.DecodeString(file.Crypto.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore nonce: %w", err)
+	}
+	ciphertext, err := hex.DecodeString(file.Crypto.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid keystore nonce length %d", len(nonce))
+	}
+
+	encodedKey, err := aead.Open(nil, nonce, ciphertext, file.additionalData())
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+
+	privateKey, err := crypto.DecodePrivateKey(crypto.StringToSignatureAlgorithm(file.SigAlgo), encodedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewInMemorySigner(flow.HexToAddress(file.Address), file.KeyIndex, privateKey, crypto.StringToHashAlgorithm(file.HashAlgo))
+}
+
+// aead derives the encryption key from the passphrase
+func (f keystoreFile) aead(passphrase string) (cipher.AEAD, error) {
+	params := f.Crypto.KDFParams
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt: %w", err)
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, keystoreKeyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// additionalData binds the ciphertext to the key metadata, so that it can't be changed without the passphrase
+func (f keystoreFile) additionalData() []byte {
+	return []byte(f.Address + "/" + strconv.FormatUint(uint64(f.KeyIndex), 10) + "/" + f.SigAlgo + "/" + f.HashAlgo)
+}
@@ -0,0 +1,247 @@
+package splash
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/glebarez/go-sqlite" // registers the sqlite driver, also used by the emulator
+	"github.com/gofrs/flock"
+)
+
+// ProgressStore stores the next block height to fetch for each named event fetcher
+type ProgressStore interface {
+	// Load returns the height saved for the key, or false if nothing was saved yet
+	Load(key string) (uint64, bool, error)
+	// Save stores the height for the key
+	Save(key string, height uint64) error
+}
+
+// TrackProgressWith stores progress under the given key in a progress store, see TrackProgressIn
+func (e EventFetcherBuilder) TrackProgressWith(store ProgressStore, key string) EventFetcherBuilder {
+	e.ProgressStore = store
+	e.ProgressKey = key
+	e.ProgressFile = ""
+	e.EndIndex = 0
+	e.FromIndex = 0
+	e.EndAtCurrentHeight = true
+	return e
+}
+
+// progressStore returns the store and key used to track progress, or a nil store if progress isn't tracked
+func (e EventFetcherBuilder) progressStore() (ProgressStore, string) {
+	if e.ProgressStore != nil {
+		return e.ProgressStore, e.ProgressKey
+	}
+	if e.ProgressFile != "" {
+		return NewFileProgressStore(filepath.Dir(e.ProgressFile)), filepath.Base(e.ProgressFile)
+	}
+	return nil, ""
+}
+
+// saveProgress saves the next block height to fetch, if progress is tracked
+func (e EventFetcherBuilder) saveProgress(height uint64) error {
+	store, key := e.progressStore()
+	if store == nil {
+		return nil
+	}
+	if err := store.Save(key, height); err != nil {
+		return fmt.Errorf("could not write progress %w", err)
+	}
+	return nil
+}
+
+// FileProgressStore stores the progress of each key in a file of that name, in the format of WriteProgressToFile.
+// Files are replaced atomically and writers of the same key, including other processes, are serialised with
+// an advisory lock on a lock file next to it, which is released by the OS if a writer crashes. Writers wait up
+// to LockTimeout for the lock. Lock files are named after the key with a .lock suffix and are left in place,
+// as removing them would let another writer lock a different file of the same name.
+type FileProgressStore struct {
+	Dir         string
+	LockTimeout time.Duration
+}
+
+// NewFileProgressStore creates a progress store keeping its files in the given directory
+func NewFileProgressStore(dir string) *FileProgressStore {
+	return &FileProgressStore{
+		Dir:         dir,
+		LockTimeout: 10 * time.Second,
+	}
+}
+
+// Load reads the height saved for the key
+func (s *FileProgressStore) Load(key string) (uint64, bool, error) {
+	fileName, err := s.path(key)
+	if err != nil {
+		return 0, false, err
+	}
+
+	present, err := exists(fileName)
+	if err != nil || !present {
+		return 0, false, err
+	}
+
+	height, err := ReadProgressFromFile(fileName)
+	if err != nil {
+		return 0, false, fmt.Errorf("could not parse progress file as block height %w", err)
+	}
+	if height < 0 {
+		return 0, false, fmt.Errorf("progress file %s holds a negative block height", fileName)
+	}
+
+	return uint64(height), true, nil
+}
+
+// Save writes the height for the key to a temporary file and moves it in place
+func (s *FileProgressStore) Save(key string, height uint64) error {
+	fileName, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := s.lock(fileName)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tmp, err := os.CreateTemp(s.Dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strconv.FormatUint(height, 10)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil { //nolint:gosec // same mode as WriteProgressToFile
+		return err
+	}
+
+	return os.Rename(tmp.Name(), fileName)
+}
+
+func (s *FileProgressStore) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("invalid progress key %q", key)
+	}
+	return filepath.Join(s.Dir, key), nil
+}
+
+// lock locks the lock file of a progress file, waiting while another writer holds it
+func (s *FileProgressStore) lock(fileName string) (func(), error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.LockTimeout)
+	defer cancel()
+
+	fileLock := flock.New(fileName + ".lock")
+	locked, err := fileLock.TryLockContext(ctx, 10*time.Millisecond)
+	if errors.Is(err, context.DeadlineExceeded) || (err == nil && !locked) {
+		return nil, fmt.Errorf("progress file %s is locked by another writer", fileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return func() { _ = fileLock.Unlock() }, nil
+}
+
+// MemoryProgressStore keeps progress in memory, which is mostly useful in tests
+type MemoryProgressStore struct {
+	mu      sync.Mutex
+	heights map[string]uint64
+}
+
+// NewMemoryProgressStore creates an empty in-memory progress store
+func NewMemoryProgressStore() *MemoryProgressStore {
+	return &MemoryProgressStore{heights: map[string]uint64{}}
+}
+
+// Load returns the height saved for the key
+func (s *MemoryProgressStore) Load(key string) (uint64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	height, found := s.heights[key]
+	return height, found, nil
+}
+
+// Save stores the height for the key
+func (s *MemoryProgressStore) Save(key string, height uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.heights[key] = height
+	return nil
+}
+
+// SQLiteProgressStore keeps progress in the splash_progress table of a SQLite database,
+// which can be shared by several fetchers and processes
+type SQLiteProgressStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteProgressStore creates a progress store in an open database, creating its table if needed
+func NewSQLiteProgressStore(db *sql.DB) (*SQLiteProgressStore, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS splash_progress (
+		key TEXT PRIMARY KEY,
+		height INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("could not create progress table: %w", err)
+	}
+	return &SQLiteProgressStore{db: db}, nil
+}
+
+// OpenSQLiteProgressStore opens or creates a SQLite database file and creates a progress store in it
+func OpenSQLiteProgressStore(fileName string) (*SQLiteProgressStore, error) {
+	db, err := sql.Open("sqlite", fileName+"?_pragma=busy_timeout(10000)")
+	if err != nil {
+		return nil, err
+	}
+	store, err := NewSQLiteProgressStore(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// Load returns the height saved for the key
+func (s *SQLiteProgressStore) Load(key string) (uint64, bool, error) {
+	var height int64
+	err := s.db.QueryRow(`SELECT height FROM splash_progress WHERE key = ?`, key).Scan(&height)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint64(height), true, nil //nolint:gosec
+}
+
+// Save stores the height for the key
+func (s *SQLiteProgressStore) Save(key string, height uint64) error {
+	_, err := s.db.Exec(`INSERT INTO splash_progress (key, height) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET height = excluded.height`, key, int64(height)) //nolint:gosec
+	return err
+}
+
+// Close closes the database of the store
+func (s *SQLiteProgressStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,137 @@
+package splash_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProgressStore(t *testing.T, store ProgressStore) {
+	t.Helper()
+
+	_, found, err := store.Load("a")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, store.Save("a", 10))
+	require.NoError(t, store.Save("b", 20))
+	require.NoError(t, store.Save("a", 11))
+
+	height, found, err := store.Load("a")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint64(11), height)
+
+	height, found, err = store.Load("b")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint64(20), height)
+}
+
+func TestProgressStore(t *testing.T) {
+
+	t.Run("memory", func(t *testing.T) {
+		testProgressStore(t, NewMemoryProgressStore())
+	})
+
+	t.Run("file", func(t *testing.T) {
+		dir := t.TempDir()
+		testProgressStore(t, NewFileProgressStore(dir))
+
+		height, err := ReadProgressFromFile(filepath.Join(dir, "a"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(11), height)
+
+		temporary, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+		require.NoError(t, err)
+		assert.Empty(t, temporary, "temporary files must be removed")
+	})
+
+	t.Run("file rejects keys that aren't file names", func(t *testing.T) {
+		err := NewFileProgressStore(t.TempDir()).Save("../a", 1)
+		assert.ErrorContains(t, err, `invalid progress key "../a"`)
+	})
+
+	t.Run("file waits for the lock", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFileProgressStore(dir)
+		store.LockTimeout = 50 * time.Millisecond
+
+		// a lock file left over by a crashed writer isn't locked
+		lockFile := filepath.Join(dir, "a.lock")
+		require.NoError(t, os.WriteFile(lockFile, nil, 0o644))
+		require.NoError(t, store.Save("a", 1))
+
+		fileLock := flock.New(lockFile)
+		require.NoError(t, fileLock.Lock())
+		err := store.Save("a", 2)
+		assert.ErrorContains(t, err, "is locked by another writer")
+
+		require.NoError(t, fileLock.Unlock())
+		require.NoError(t, store.Save("a", 2))
+	})
+
+	t.Run("file serialises concurrent writers", func(t *testing.T) {
+		dir := t.TempDir()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(height uint64) {
+				defer wg.Done()
+				assert.NoError(t, NewFileProgressStore(dir).Save("a", height))
+			}(uint64(i))
+		}
+		wg.Wait()
+
+		_, found, err := NewFileProgressStore(dir).Load("a")
+		require.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "progress.db")
+		store, err := OpenSQLiteProgressStore(fileName)
+		require.NoError(t, err)
+		testProgressStore(t, store)
+		require.NoError(t, store.Close())
+
+		reopened, err := OpenSQLiteProgressStore(fileName)
+		require.NoError(t, err)
+		defer reopened.Close()
+
+		height, found, err := reopened.Load("b")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, uint64(20), height)
+	})
+
+	t.Run("event fetcher", func(t *testing.T) {
+		g, err := NewInMemoryTestConnector("examples", false)
+		require.NoError(t, err)
+
+		store := NewMemoryProgressStore()
+		require.NoError(t, store.Save("other", 1))
+
+		fetcher := g.EventFetcher().Event("A.0ae53cb6e3f42a79.FlowToken.TokensMinted").TrackProgressWith(store, "minted")
+		_, err = fetcher.Run(context.Background())
+		require.NoError(t, err)
+
+		height, found, err := store.Load("minted")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Positive(t, height)
+
+		height, _, err = store.Load("other")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), height)
+	})
+}
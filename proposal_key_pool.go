@@ -0,0 +1,407 @@
+package splash
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// KeySelectionStrategy defines how a ProposalKeyPool picks the next free key
+type KeySelectionStrategy int
+
+const (
+	// RoundRobin hands out keys in order of their position in the pool
+	RoundRobin KeySelectionStrategy = iota
+	// LeastRecentlyUsed hands out the key that has been idle for the longest time
+	LeastRecentlyUsed
+)
+
+// sequenceNumberMismatchErrorCode is the FVM error code for an invalid proposal key sequence number
+const sequenceNumberMismatchErrorCode = 1007
+
+// leaseSettleTimeout is how long a proposal key stays leased if its sequence number doesn't move on chain.
+// Transactions expire 600 blocks after their reference block, about 10 minutes on Flow networks.
+const leaseSettleTimeout = 15 * time.Minute
+
+type (
+	// ProposalKeyPool hands out distinct proposal keys of a single account, so that
+	// several transactions proposed by this account can be in flight at the same time.
+	// Sequence numbers are tracked locally and re-synced from the chain after a mismatch.
+	ProposalKeyPool struct {
+		Address  flow.Address
+		Strategy KeySelectionStrategy
+
+		connector *Connector
+		mu        sync.Mutex
+		keys      []*pooledKey
+		next      int
+		available chan struct{}
+		// sent holds the leases of sent transactions with the time they were sent, see watch
+		sent     map[*ProposalKeyLease]time.Time
+		watching bool
+	}
+
+	pooledKey struct {
+		index          uint32
+		sequenceNumber uint64
+		inUse          bool
+		stale          bool
+		lastUsed       time.Time
+	}
+
+	// ProposalKeyLease is a key borrowed from a ProposalKeyPool for a single transaction
+	ProposalKeyLease struct {
+		Index          uint32
+		SequenceNumber uint64
+
+		pool *ProposalKeyPool
+		key  *pooledKey
+		once sync.Once
+	}
+)
+
+// UseProposalKeyPool creates a proposal key pool for the given account (prefixed with the network name
+// as usual) and registers it with the connector. All transactions proposed by this account will take
+// their proposal key from the pool.
+//
+// The proposer's signer must be able to sign with every key in the pool. If no key indexes are given,
+// all non-revoked full weight keys with the same public key as the account's key in flow.json are used,
+// as local signers sign with that one private key whatever the key index. Explicit key indexes are
+// taken as they are, e.g. for a RemoteSigner that holds a different private key for each index.
+func (c *Connector) UseProposalKeyPool(ctx context.Context, accountName string, strategy KeySelectionStrategy, keyIndexes ...uint32) (*ProposalKeyPool, error) {
+	account, err := c.AccountE(accountName)
+	if err != nil {
+		return nil, err
+	}
+
+	onChainAccount, err := c.Services.GetAccount(ctx, account.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := map[uint32]bool{}
+	for _, idx := range keyIndexes {
+		wanted[idx] = true
+	}
+
+	var publicKey crypto.PublicKey
+	if len(wanted) == 0 {
+		privateKey, err := account.Key.PrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("can't determine the public key of account %s, pass the key indexes to use: %w", account.Address, err)
+		}
+		publicKey = (*privateKey).PublicKey()
+	}
+
+	pool := &ProposalKeyPool{
+		Address:   account.Address,
+		Strategy:  strategy,
+		connector: c,
+	}
+	for _, key := range onChainAccount.Keys {
+		if len(wanted) > 0 {
+			if !wanted[key.Index] {
+				continue
+			}
+			delete(wanted, key.Index)
+		} else if key.Revoked || key.Weight < flow.AccountKeyWeightThreshold || !key.PublicKey.Equals(publicKey) {
+			continue
+		}
+		pool.keys = append(pool.keys, &pooledKey{
+			index:          key.Index,
+			sequenceNumber: key.SequenceNumber,
+		})
+	}
+
+	if len(wanted) > 0 {
+		return nil, fmt.Errorf("account %s has no keys with indexes %v", account.Address, keyIndexes)
+	}
+	if len(pool.keys) == 0 {
+		return nil, fmt.Errorf("account %s has no keys usable for proposing transactions", account.Address)
+	}
+
+	pool.available = make(chan struct{}, len(pool.keys))
+	for range pool.keys {
+		pool.available <- struct{}{}
+	}
+
+	c.keyPoolsMu.Lock()
+	defer c.keyPoolsMu.Unlock()
+	if c.keyPools == nil {
+		c.keyPools = map[flow.Address]*ProposalKeyPool{}
+	}
+	c.keyPools[account.Address] = pool
+
+	return pool, nil
+}
+
+// ProposalKeyPool returns the proposal key pool registered for the given address, if any
+func (c *Connector) ProposalKeyPool(address flow.Address) *ProposalKeyPool {
+	c.keyPoolsMu.RLock()
+	defer c.keyPoolsMu.RUnlock()
+	return c.keyPools[address]
+}
+
+// leaseProposalKey borrows a key from the pool registered for the address. It returns nil
+// if there is no such pool, in which case the account's own key should be used.
+func (c *Connector) leaseProposalKey(ctx context.Context, address flow.Address) (*ProposalKeyLease, error) {
+	pool := c.ProposalKeyPool(address)
+	if pool == nil {
+		return nil, nil
+	}
+
+	return pool.Acquire(ctx)
+}
+
+// Size returns the number of keys in the pool
+func (p *ProposalKeyPool) Size() int {
+	return len(p.keys)
+}
+
+// Acquire waits for a free key and leases it. The lease must be released once the transaction
+// has been included in a block or has failed to be sent.
+func (p *ProposalKeyPool) Acquire(ctx context.Context) (*ProposalKeyLease, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.available:
+	}
+
+	p.mu.Lock()
+	key := p.pick()
+	key.inUse = true
+	key.lastUsed = time.Now()
+	stale := key.stale
+	p.mu.Unlock()
+
+	if stale {
+		if err := p.resync(ctx, key); err != nil {
+			p.release(key, false, true)
+			return nil, err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return &ProposalKeyLease{
+		Index:          key.index,
+		SequenceNumber: key.sequenceNumber,
+		pool:           p,
+		key:            key,
+	}, nil
+}
+
+// pick selects a free key according to the strategy. The caller must hold the lock
+// and a token from the available channel, which guarantees that a free key exists.
+func (p *ProposalKeyPool) pick() *pooledKey {
+	var picked *pooledKey
+	switch p.Strategy {
+	case LeastRecentlyUsed:
+		for _, key := range p.keys {
+			if !key.inUse && (picked == nil || key.lastUsed.Before(picked.lastUsed)) {
+				picked = key
+			}
+		}
+	default:
+		for i := 0; i < len(p.keys); i++ {
+			key := p.keys[(p.next+i)%len(p.keys)]
+			if !key.inUse {
+				picked = key
+				p.next = (p.next + i + 1) % len(p.keys)
+				break
+			}
+		}
+	}
+
+	return picked
+}
+
+// Sync refreshes the sequence numbers of all keys in the pool from the chain. Leased keys are
+// skipped, as their transactions may not have been included yet; they are re-synced once released.
+func (p *ProposalKeyPool) Sync(ctx context.Context) error {
+	account, err := p.connector.Services.GetAccount(ctx, p.Address)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, key := range p.keys {
+		if key.inUse {
+			key.stale = true
+			continue
+		}
+		for _, onChainKey := range account.Keys {
+			if onChainKey.Index == key.index {
+				key.sequenceNumber = onChainKey.SequenceNumber
+				key.stale = false
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *ProposalKeyPool) resync(ctx context.Context, key *pooledKey) error {
+	account, err := p.connector.Services.GetAccount(ctx, p.Address)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, onChainKey := range account.Keys {
+		if onChainKey.Index == key.index {
+			key.sequenceNumber = onChainKey.SequenceNumber
+			key.stale = false
+			return nil
+		}
+	}
+
+	return fmt.Errorf("key %d not found in account %s", key.index, p.Address)
+}
+
+func (p *ProposalKeyPool) release(key *pooledKey, used, stale bool) {
+	p.mu.Lock()
+	if used {
+		key.sequenceNumber++
+	}
+	if stale {
+		key.stale = true
+	}
+	key.inUse = false
+	p.mu.Unlock()
+
+	p.available <- struct{}{}
+}
+
+// Release returns the key to the pool. Set used to true if the transaction was included in a block,
+// which means the sequence number on chain has been incremented. If err is a sequence number
+// mismatch, the key is re-synced from the chain before it is handed out again.
+// Releasing a nil or already released lease is a no-op.
+func (l *ProposalKeyLease) Release(used bool, err error) {
+	if l == nil {
+		return
+	}
+
+	l.once.Do(func() {
+		l.pool.unwatch(l)
+		mismatch := err != nil && isSequenceNumberMismatch(err)
+		l.pool.release(l.key, used && !mismatch, mismatch)
+	})
+}
+
+// settle releases the lease based on the observed transaction result and returns true if it has been
+// released. Leases are only released once the transaction has been finalized (or expired), so that
+// the next transaction proposed with the same key can't be ordered before it.
+func (l *ProposalKeyLease) settle(result *TransactionRunResult) bool {
+	if l == nil {
+		return true
+	}
+
+	switch {
+	case result.Status == flow.TransactionStatusExpired:
+		l.Release(false, nil)
+	case result.Status >= flow.TransactionStatusFinalized:
+		l.Release(true, result.Error)
+	default:
+		return false
+	}
+	return true
+}
+
+// releaseStale returns the key to the pool without knowing whether the transaction was included,
+// so its sequence number is re-synced from the chain before it's handed out again
+func (l *ProposalKeyLease) releaseStale() {
+	l.once.Do(func() {
+		l.pool.unwatch(l)
+		l.pool.release(l.key, false, true)
+	})
+}
+
+// releaseAt returns the key to the pool once the chain has moved its sequence number past the lease
+func (l *ProposalKeyLease) releaseAt(sequenceNumber uint64) {
+	l.once.Do(func() {
+		l.pool.unwatch(l)
+		l.pool.mu.Lock()
+		l.key.sequenceNumber = sequenceNumber
+		l.key.stale = false
+		l.key.inUse = false
+		l.pool.mu.Unlock()
+
+		l.pool.available <- struct{}{}
+	})
+}
+
+// watch makes the pool release the lease once its transaction has been included, so that the key
+// returns to the pool even if nobody waits for the transaction. All sent transactions of a pool are
+// watched by a single goroutine that checks the account's sequence numbers.
+func (l *ProposalKeyLease) watch() {
+	p := l.pool
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.sent == nil {
+		p.sent = map[*ProposalKeyLease]time.Time{}
+	}
+	p.sent[l] = time.Now()
+	if !p.watching {
+		p.watching = true
+		go p.watchLeases()
+	}
+}
+
+func (p *ProposalKeyPool) unwatch(l *ProposalKeyLease) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.sent, l)
+}
+
+// watchLeases releases the leases of sent transactions once the sequence numbers of their keys have moved
+// on chain, or as stale once leaseSettleTimeout has passed. It stops when no sent leases are left.
+func (p *ProposalKeyPool) watchLeases() {
+	for {
+		time.Sleep(defaultStatusPollInterval)
+
+		p.mu.Lock()
+		if len(p.sent) == 0 {
+			p.watching = false
+			p.mu.Unlock()
+			return
+		}
+		sent := make(map[*ProposalKeyLease]time.Time, len(p.sent))
+		for l, sentAt := range p.sent {
+			sent[l] = sentAt
+		}
+		p.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), defaultStatusPollInterval*10)
+		account, err := p.connector.Services.GetAccount(ctx, p.Address)
+		cancel()
+
+		for l, sentAt := range sent {
+			if err == nil {
+				for _, onChainKey := range account.Keys {
+					if onChainKey.Index == l.Index && onChainKey.SequenceNumber > l.SequenceNumber {
+						l.releaseAt(onChainKey.SequenceNumber)
+					}
+				}
+			}
+			if time.Since(sentAt) > leaseSettleTimeout {
+				l.releaseStale()
+			}
+		}
+	}
+}
+
+// isSequenceNumberMismatch returns true if the error was caused by an outdated proposal key sequence number
+func isSequenceNumberMismatch(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return extractErrorCode(err) == sequenceNumberMismatchErrorCode
+}
@@ -0,0 +1,128 @@
+package splash
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// defaultRemoteSignerTimeout is the timeout of a single signing request
+const defaultRemoteSignerTimeout = 30 * time.Second
+
+type (
+	// RemoteSigner delegates signing to an HTTP service that holds the private key. It POSTs a
+	// RemoteSignRequest as JSON to the URL and expects a RemoteSignResponse in return.
+	RemoteSigner struct {
+		URL    string
+		Header http.Header
+		Client *http.Client
+
+		address  flow.Address
+		keyIndex uint32
+	}
+
+	// RemoteSignRequest is the body sent to a remote signer
+	RemoteSignRequest struct {
+		Address  string `json:"address"`
+		KeyIndex uint32 `json:"keyIndex"`
+		// Message is the hex encoded, domain tagged message to hash and sign
+		Message string `json:"message"`
+	}
+
+	// RemoteSignResponse is the body returned by a remote signer
+	RemoteSignResponse struct {
+		// Signature is the hex encoded signature
+		Signature string `json:"signature"`
+	}
+)
+
+// NewRemoteSigner returns a signer for the given account key that calls the signing service at url
+func NewRemoteSigner(url string, address flow.Address, keyIndex uint32) *RemoteSigner {
+	return &RemoteSigner{
+		URL:      url,
+		Header:   http.Header{},
+		Client:   &http.Client{Timeout: defaultRemoteSignerTimeout},
+		address:  address,
+		keyIndex: keyIndex,
+	}
+}
+
+// WithHeader adds a header, e.g. for authentication, to every signing request
+func (s *RemoteSigner) WithHeader(key, value string) *RemoteSigner {
+	s.Header.Add(key, value)
+	return s
+}
+
+// ForKeyIndex returns a copy of the signer that signs for another key of the same account
+func (s *RemoteSigner) ForKeyIndex(keyIndex uint32) Signer {
+	signer := *s
+	signer.keyIndex = keyIndex
+	return &signer
+}
+
+// Address returns the address of the account
+func (s *RemoteSigner) Address() flow.Address {
+	return s.address
+}
+
+// KeyIndex returns the index of the account key
+func (s *RemoteSigner) KeyIndex() uint32 {
+	return s.keyIndex
+}
+
+// Sign asks the remote service to sign the message
+func (s *RemoteSigner) Sign(message []byte) ([]byte, error) {
+	return s.SignContext(context.Background(), message)
+}
+
+// SignContext asks the remote service to sign the message, giving up when the context is cancelled
+func (s *RemoteSigner) SignContext(ctx context.Context, message []byte) ([]byte, error) {
+	body, err := json.Marshal(RemoteSignRequest{
+		Address:  s.address.HexWithPrefix(),
+		KeyIndex: s.keyIndex,
+		Message:  hex.EncodeToString(message),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range s.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("remote signer returned %s: %s", resp.Status, strings.TrimSpace(string(text)))
+	}
+
+	var signResponse RemoteSignResponse
+	if err := json.NewDecoder(resp.Body).Decode(&signResponse); err != nil {
+		return nil, fmt.Errorf("invalid remote signer response: %w", err)
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(signResponse.Signature, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer signature: %w", err)
+	}
+
+	return signature, nil
+}
@@ -0,0 +1,106 @@
+package splash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy defines how operations against the access node are retried when they fail with a transient error
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Zero or one disables retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts
+	MaxBackoff time.Duration
+	// Multiplier is applied to the delay after every retry
+	Multiplier float64
+	// Retryable decides if an error is worth retrying. If nil, IsTransientError is used.
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by network connectors
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Retryable:      IsTransientError,
+	}
+}
+
+// NoRetryPolicy returns a retry policy that makes a single attempt
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// IsTransientError returns true if the error is likely to go away when the operation is repeated:
+// an unavailable or rate limited access node, a timed out request or an outdated proposal key sequence number
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded, codes.Aborted:
+		return true
+	}
+
+	return isSequenceNumberMismatch(err)
+}
+
+// Backoff returns the delay before the given retry (starting from 1)
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		if p.Multiplier > 1 {
+			delay *= p.Multiplier
+		}
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+
+	return time.Duration(delay)
+}
+
+// Do calls fn until it succeeds, fails with an error that isn't retryable, the attempts
+// are exhausted or the context is done. The last error is returned.
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsTransientError
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(p.Backoff(attempt)):
+		}
+	}
+}
+
+// retry runs fn with the given policy, logging failed attempts
+func (c *Connector) retry(ctx context.Context, policy RetryPolicy, operation string, fn func() error) error {
+	attempt := 0
+	return policy.Do(ctx, func() error {
+		attempt++
+		err := fn()
+		if err != nil && attempt < policy.MaxAttempts {
+			c.Logger.Debug(fmt.Sprintf("%s failed (attempt %d of %d): %v", operation, attempt, policy.MaxAttempts, err))
+		}
+		return err
+	})
+}
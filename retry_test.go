@@ -0,0 +1,102 @@
+package splash_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsTransientError(t *testing.T) {
+	assert.True(t, IsTransientError(status.Error(codes.Unavailable, "node is down")))
+	assert.True(t, IsTransientError(fmt.Errorf("wrapped: %w", status.Error(codes.ResourceExhausted, "rate limited"))))
+	assert.True(t, IsTransientError(errors.New("[Error Code: 1007] invalid proposal key: sequence number mismatch")))
+	assert.False(t, IsTransientError(status.Error(codes.InvalidArgument, "bad script")))
+	assert.False(t, IsTransientError(errors.New("[Error Code: 1101] cadence runtime error")))
+	assert.False(t, IsTransientError(context.Canceled))
+	assert.False(t, IsTransientError(nil))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     3 * time.Millisecond,
+		Multiplier:     2,
+	}
+
+	t.Run("Backoff grows exponentially up to the maximum", func(t *testing.T) {
+		assert.Equal(t, time.Millisecond, policy.Backoff(1))
+		assert.Equal(t, 2*time.Millisecond, policy.Backoff(2))
+		assert.Equal(t, 3*time.Millisecond, policy.Backoff(3))
+		assert.Equal(t, 3*time.Millisecond, policy.Backoff(10))
+	})
+
+	t.Run("Retries transient errors until success", func(t *testing.T) {
+		attempts := 0
+		err := policy.Do(context.Background(), func() error {
+			attempts++
+			if attempts < 3 {
+				return status.Error(codes.Unavailable, "node is down")
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("Gives up after max attempts", func(t *testing.T) {
+		attempts := 0
+		err := policy.Do(context.Background(), func() error {
+			attempts++
+			return status.Error(codes.Unavailable, "node is down")
+		})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, 4, attempts)
+	})
+
+	t.Run("Doesn't retry permanent errors", func(t *testing.T) {
+		attempts := 0
+		err := policy.Do(context.Background(), func() error {
+			attempts++
+			return errors.New("boom")
+		})
+		assert.EqualError(t, err, "boom")
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("Custom retryable errors", func(t *testing.T) {
+		custom := policy
+		custom.Retryable = func(err error) bool { return err.Error() == "again" }
+
+		attempts := 0
+		err := custom.Do(context.Background(), func() error {
+			attempts++
+			return errors.New("again")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 4, attempts)
+	})
+
+	t.Run("Builders override the connector policy", func(t *testing.T) {
+		g, err := NewInMemoryTestConnector("examples", false)
+		require.NoError(t, err)
+
+		g.WithRetryPolicy(policy)
+
+		assert.Equal(t, 4, g.Transaction("").RetryPolicy().MaxAttempts)
+		assert.Equal(t, 1, g.Transaction("").NoRetry().RetryPolicy().MaxAttempts)
+		assert.Equal(t, 4, g.Script("").RetryPolicy().MaxAttempts)
+		assert.Equal(t, 7, g.Script("").Retry(RetryPolicy{MaxAttempts: 7}).RetryPolicy().MaxAttempts)
+		assert.Equal(t, 1, g.EventFetcher().NoRetry().RetryPolicy().MaxAttempts)
+		assert.Equal(t, 4, g.ScriptBatch().RetryPolicy().MaxAttempts)
+		assert.Equal(t, 1, g.ScriptBatch().NoRetry().RetryPolicy().MaxAttempts)
+	})
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
+	"time"
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/flow-go-sdk"
@@ -17,6 +17,19 @@ type FlowScriptBuilder struct {
 	FileName       string
 	Arguments      []cadence.Value
 	ScriptAsString string
+
+	namedArguments []namedArgument
+	retryPolicy    *RetryPolicy
+	query          *flowkit.ScriptQuery
+	noCache        bool
+	err            error
+}
+
+// ScriptResult is the value returned by a script, together with the block the script was executed against
+type ScriptResult struct {
+	Value       cadence.Value
+	BlockID     flow.Identifier
+	BlockHeight uint64
 }
 
 // Script start a script builder with the inline script as body
@@ -39,11 +52,62 @@ func (c *Connector) ScriptFromFile(filename string) FlowScriptBuilder {
 	}
 }
 
+// AtHeight executes the script against the state at the given block height instead of the latest block
+func (t FlowScriptBuilder) AtHeight(height uint64) FlowScriptBuilder {
+	t.query = &flowkit.ScriptQuery{Height: height}
+	return t
+}
+
+// AtBlockID executes the script against the state at the given block instead of the latest block
+func (t FlowScriptBuilder) AtBlockID(blockID flow.Identifier) FlowScriptBuilder {
+	t.query = &flowkit.ScriptQuery{ID: blockID}
+	return t
+}
+
+// NoCache bypasses the script cache of the connector for this script
+func (t FlowScriptBuilder) NoCache() FlowScriptBuilder {
+	t.noCache = true
+	return t
+}
+
+// Retry overrides the retry policy of the connector for this script
+func (t FlowScriptBuilder) Retry(policy RetryPolicy) FlowScriptBuilder {
+	t.retryPolicy = &policy
+	return t
+}
+
+// NoRetry disables retries for this script
+func (t FlowScriptBuilder) NoRetry() FlowScriptBuilder {
+	return t.Retry(NoRetryPolicy())
+}
+
+// RetryPolicy returns the retry policy in effect for this script
+func (t FlowScriptBuilder) RetryPolicy() RetryPolicy {
+	if t.retryPolicy != nil {
+		return *t.retryPolicy
+	}
+	return t.Connector.RetryPolicy
+}
+
+// Err returns the first error recorded while building the script, if any
+func (t FlowScriptBuilder) Err() error {
+	return t.err
+}
+
+// fail records a deferred error that will be returned by RunReturns. Only the first error is kept.
+func (t *FlowScriptBuilder) fail(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
 // AccountArgument add an account as an argument
 func (t FlowScriptBuilder) AccountArgument(key string) FlowScriptBuilder {
-	f := t.Connector
-
-	account := f.Account(key)
+	account, err := t.Connector.AccountE(key)
+	if err != nil {
+		t.fail(err)
+		return t
+	}
 	return t.Argument(cadence.BytesToAddress(account.Address.Bytes()))
 }
 
@@ -57,7 +121,32 @@ func (t FlowScriptBuilder) RawAccountArgument(key string) FlowScriptBuilder {
 
 // DateStringAsUnixTimestamp sends a dateString parsed in the timezone as a unix timeszone ufix
 func (t FlowScriptBuilder) DateStringAsUnixTimestamp(dateString, timezone string) FlowScriptBuilder {
-	return t.UFix64Argument(parseTime(dateString, timezone))
+	value, err := parseTimeInZone(dateString, timezone)
+	if err != nil {
+		t.fail(err)
+		return t
+	}
+	return t.DateArgument(value)
+}
+
+// DateStringArgument sends a dateString parsed in the location as a unix timestamp ufix
+func (t FlowScriptBuilder) DateStringArgument(dateString string, location *time.Location) FlowScriptBuilder {
+	value, err := ParseTimeInLocation(dateString, location)
+	if err != nil {
+		t.fail(err)
+		return t
+	}
+	return t.DateArgument(value)
+}
+
+// DateArgument sends a time as a unix timestamp ufix, keeping fractions of a second
+func (t FlowScriptBuilder) DateArgument(value time.Time) FlowScriptBuilder {
+	timestamp, err := TimeToUFix64(value)
+	if err != nil {
+		t.fail(err)
+		return t
+	}
+	return t.Argument(timestamp)
 }
 
 // Argument add an argument to the transaction
@@ -181,7 +270,8 @@ func (t FlowScriptBuilder) Word64Argument(value uint64) FlowScriptBuilder {
 func (t FlowScriptBuilder) Fix64Argument(value string) FlowScriptBuilder {
 	amount, err := cadence.NewFix64(value)
 	if err != nil {
-		panic(err)
+		t.fail(err)
+		return t
 	}
 	return t.Argument(amount)
 }
@@ -190,56 +280,231 @@ func (t FlowScriptBuilder) Fix64Argument(value string) FlowScriptBuilder {
 func (t FlowScriptBuilder) UFix64Argument(value string) FlowScriptBuilder {
 	amount, err := cadence.NewUFix64(value)
 	if err != nil {
-		panic(err)
+		t.fail(err)
+		return t
 	}
 	return t.Argument(amount)
 }
 
+// DecimalArgument add a UFix64 or Fix64 Argument to the script, depending on the sign type of the decimal
+func (t FlowScriptBuilder) DecimalArgument(value Decimal) FlowScriptBuilder {
+	return t.Argument(value.Cadence())
+}
+
+// StructArgument add a struct Argument of a contract type such as "Debug.Foo" to the script, built from a Go value.
+// See Connector.StructValue for how the fields are mapped.
+func (t FlowScriptBuilder) StructArgument(typeName string, value any) FlowScriptBuilder {
+	structValue, err := t.Connector.StructValue(typeName, value)
+	if err != nil {
+		t.fail(err)
+		return t
+	}
+	return t.Argument(structValue)
+}
+
+// NamedArgument adds an argument for the parameter with the given name. Named arguments are
+// placed according to the parameter order of the script's main function when it's run.
+func (t FlowScriptBuilder) NamedArgument(name string, value cadence.Value) FlowScriptBuilder {
+	t.namedArguments = append(t.namedArguments, namedArgument{Name: name, Value: value})
+	return t
+}
+
+// ArgumentsByName adds arguments by parameter name, see NamedArgument
+func (t FlowScriptBuilder) ArgumentsByName(arguments map[string]cadence.Value) FlowScriptBuilder {
+	t.namedArguments = append(t.namedArguments, namedArguments(arguments)...)
+	return t
+}
+
+// Args converts Go values to Cadence arguments, guided by the parameter types of the script's main function.
+// See ToCadenceValue for the supported conversions.
+func (t FlowScriptBuilder) Args(values ...any) FlowScriptBuilder {
+	code, err := t.code()
+	if err != nil {
+		t.fail(err)
+		return t
+	}
+
+	args, err := convertArguments(code, len(t.Arguments), values)
+	if err != nil {
+		t.fail(err)
+		return t
+	}
+	t.Arguments = append(t.Arguments, args...)
+	return t
+}
+
+func (t FlowScriptBuilder) filePath() string {
+	return fmt.Sprintf("./scripts/%s.cdc", t.FileName)
+}
+
+// code returns the inline script or reads it from the script file
+func (t FlowScriptBuilder) code() ([]byte, error) {
+	if t.ScriptAsString != "" {
+		return []byte(t.ScriptAsString), nil
+	}
+	return t.Connector.State.ReaderWriter().ReadFile(t.filePath())
+}
+
 // Run executes a read only script
 func (t FlowScriptBuilder) Run(ctx context.Context) {
 	result := t.RunFailOnError(ctx)
 	log.Printf("Script run from result: %v\n", CadenceValueToJSONString(result))
 }
 
-// RunReturns executes a read only script
+// RunReturns executes a read only script, against the latest block unless AtHeight or AtBlockID is set
 func (t FlowScriptBuilder) RunReturns(ctx context.Context) (cadence.Value, error) {
+	query := flowkit.LatestScriptQuery
+	if t.query != nil {
+		query = *t.query
+	}
+
+	return t.execute(ctx, query, nil)
+}
+
+// RunWithResult executes a read only script like RunReturns and reports the block it was executed against.
+// Without AtHeight or AtBlockID, the latest block is looked up first, so that the reported height is exact.
+func (t FlowScriptBuilder) RunWithResult(ctx context.Context) (*ScriptResult, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	block, err := t.block(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := t.runAt(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScriptResult{
+		Value:       value,
+		BlockID:     block.ID,
+		BlockHeight: block.Height,
+	}, nil
+}
+
+// block fetches the block the script runs against
+func (t FlowScriptBuilder) block(ctx context.Context) (*flow.Block, error) {
+	blockQuery := flowkit.LatestBlockQuery
+	if t.query != nil {
+		if t.query.ID != flow.EmptyID {
+			blockQuery = flowkit.BlockQuery{ID: &t.query.ID}
+		} else {
+			blockQuery = flowkit.BlockQuery{Height: t.query.Height}
+		}
+	}
+
+	var block *flow.Block
+	err := t.Connector.retry(ctx, t.RetryPolicy(), "get block", func() (err error) {
+		block, err = t.Connector.Services.GetBlock(ctx, blockQuery)
+		return err
+	})
+	return block, err
+}
+
+// runAt executes the script against a block that has already been fetched
+func (t FlowScriptBuilder) runAt(ctx context.Context, block *flow.Block) (cadence.Value, error) {
+	return t.execute(ctx, flowkit.ScriptQuery{ID: block.ID}, block)
+}
+
+// execute runs the script, going through the script cache of the connector if there is one.
+// The block is optional, it saves looking up the block height for the cache key.
+func (t FlowScriptBuilder) execute(ctx context.Context, query flowkit.ScriptQuery, block *flow.Block) (cadence.Value, error) {
+
+	if t.err != nil {
+		return nil, t.err
+	}
 
 	f := t.Connector
-	scriptFilePath := fmt.Sprintf("./scripts/%s.cdc", t.FileName)
+	scriptFilePath := t.filePath()
 
-	var err error
-	script := []byte(t.ScriptAsString)
-	if t.ScriptAsString == "" {
-		script, err = f.State.ReaderWriter().ReadFile(scriptFilePath)
+	script, err := t.code()
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := resolveArguments(script, t.Arguments, t.namedArguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateArguments(script, args); err != nil {
+		return nil, err
+	}
+
+	cacheKey := ""
+	if f.ScriptCache != nil && !t.noCache {
+		if block == nil && (query.Latest || query.ID != flow.EmptyID) {
+			// the cache key needs the height of the block the script runs against
+			blockQuery := flowkit.LatestBlockQuery
+			if !query.Latest {
+				blockQuery = flowkit.BlockQuery{ID: &query.ID}
+			}
+			err = f.retry(ctx, t.RetryPolicy(), "get block", func() (err error) {
+				block, err = f.Services.GetBlock(ctx, blockQuery)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			query = flowkit.ScriptQuery{ID: block.ID}
+		}
+
+		height := query.Height
+		if block != nil {
+			height = block.Height
+		}
+
+		cacheKey, err = scriptCacheKey(script, args, height)
 		if err != nil {
 			return nil, err
 		}
+		if value, found := f.ScriptCache.Get(cacheKey); found {
+			f.scriptCacheHits.Add(1)
+			return value, nil
+		}
+		f.scriptCacheMisses.Add(1)
 	}
 
-	result, err := f.Services.ExecuteScript(
-		ctx,
-		flowkit.Script{
-			Code:     script,
-			Args:     t.Arguments,
-			Location: scriptFilePath,
-		},
-		flowkit.LatestScriptQuery,
-	)
+	var result cadence.Value
+	err = f.retry(ctx, t.RetryPolicy(), fmt.Sprintf("script %s", t.FileName), func() (err error) {
+		if f.serialScripts {
+			f.scriptMu.Lock()
+			defer f.scriptMu.Unlock()
+		}
+		result, err = f.Services.ExecuteScript(
+			ctx,
+			flowkit.Script{
+				Code:     script,
+				Args:     args,
+				Location: scriptFilePath,
+			},
+			query,
+		)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if cacheKey != "" {
+		f.ScriptCache.Set(cacheKey, result)
+	}
+
 	if t.ScriptAsString == "" {
 		f.Logger.Debug(fmt.Sprintf("Script run from path %s\n", scriptFilePath))
 	}
 	return result, nil
 }
 
+// RunFailOnError executes a read only script and panics if it fails
 func (t FlowScriptBuilder) RunFailOnError(ctx context.Context) cadence.Value {
 	result, err := t.RunReturns(ctx)
 	if err != nil {
 		t.Connector.Logger.Error(fmt.Sprintf("Error executing script: %s output %v", t.FileName, err))
-		os.Exit(1)
+		panic(err)
 	}
 	return result
 }
@@ -249,7 +514,17 @@ func (t FlowScriptBuilder) RunReturnsJSONString(ctx context.Context) string {
 	return CadenceValueToJSONString(t.RunFailOnError(ctx))
 }
 
-// RunReturnsInterface runs the script and returns interface{}
-func (t FlowScriptBuilder) RunReturnsInterface(ctx context.Context) interface{} {
-	return CadenceValueToInterface(t.RunFailOnError(ctx))
+// RunReturnsInterface runs the script and returns interface{}, see CadenceValueToInterface for the options
+func (t FlowScriptBuilder) RunReturnsInterface(ctx context.Context, opts ...ConversionOption) interface{} {
+	return CadenceValueToInterface(t.RunFailOnError(ctx), opts...)
+}
+
+// RunInto runs the script and decodes the result into the Go value pointed to by target, see DecodeCadence
+func (t FlowScriptBuilder) RunInto(ctx context.Context, target any) error {
+	result, err := t.RunReturns(ctx)
+	if err != nil {
+		return err
+	}
+
+	return DecodeCadence(result, target)
 }
@@ -0,0 +1,154 @@
+package splash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flowkit/v2"
+)
+
+// ScriptBatch runs many scripts concurrently against the same block, so that their results form a consistent snapshot.
+// Scripts run against the in-memory emulator are executed one at a time.
+type ScriptBatch struct {
+	Connector       *Connector
+	Scripts         []FlowScriptBuilder
+	NumberOfWorkers int
+
+	blockQuery  flowkit.BlockQuery
+	retryPolicy *RetryPolicy
+}
+
+// ScriptBatchResult holds the values and errors of the scripts of a batch, in the order the scripts were added
+type ScriptBatchResult struct {
+	BlockID     flow.Identifier
+	BlockHeight uint64
+	Values      []cadence.Value
+	Errors      []error
+}
+
+// ScriptBatch creates a batch of scripts, run against the latest block unless AtHeight or AtBlockID is set
+func (c *Connector) ScriptBatch(scripts ...FlowScriptBuilder) ScriptBatch {
+	return ScriptBatch{
+		Connector:       c,
+		Scripts:         scripts,
+		NumberOfWorkers: 20,
+		blockQuery:      flowkit.LatestBlockQuery,
+	}
+}
+
+// Add adds scripts to the batch
+func (b ScriptBatch) Add(scripts ...FlowScriptBuilder) ScriptBatch {
+	b.Scripts = append(b.Scripts[:len(b.Scripts):len(b.Scripts)], scripts...)
+	return b
+}
+
+// Workers sets the maximum number of scripts that are executed at the same time
+func (b ScriptBatch) Workers(workers int) ScriptBatch {
+	b.NumberOfWorkers = workers
+	return b
+}
+
+// AtHeight runs all scripts against the block at the given height
+func (b ScriptBatch) AtHeight(height uint64) ScriptBatch {
+	b.blockQuery = flowkit.BlockQuery{Height: height}
+	return b
+}
+
+// AtBlockID runs all scripts against the block with the given ID
+func (b ScriptBatch) AtBlockID(blockID flow.Identifier) ScriptBatch {
+	b.blockQuery = flowkit.BlockQuery{ID: &blockID}
+	return b
+}
+
+// Retry overrides the retry policy of the connector for resolving the block of the batch.
+// Each script keeps its own policy, see FlowScriptBuilder.Retry.
+func (b ScriptBatch) Retry(policy RetryPolicy) ScriptBatch {
+	b.retryPolicy = &policy
+	return b
+}
+
+// NoRetry disables retries for resolving the block of the batch
+func (b ScriptBatch) NoRetry() ScriptBatch {
+	return b.Retry(NoRetryPolicy())
+}
+
+// RetryPolicy returns the retry policy in effect for resolving the block of the batch
+func (b ScriptBatch) RetryPolicy() RetryPolicy {
+	if b.retryPolicy != nil {
+		return *b.retryPolicy
+	}
+	return b.Connector.RetryPolicy
+}
+
+// Run executes the scripts and returns their results in input order. The block is resolved once and all scripts
+// are executed against it, overriding AtHeight and AtBlockID of the individual scripts. A failing script doesn't
+// stop the others. If the context is cancelled, scripts that haven't started yet fail with the context's error.
+// The returned error is only set when the block can't be resolved.
+func (b ScriptBatch) Run(ctx context.Context) (*ScriptBatchResult, error) {
+	var block *flow.Block
+	err := b.Connector.retry(ctx, b.RetryPolicy(), "get block", func() (err error) {
+		block, err = b.Connector.Services.GetBlock(ctx, b.blockQuery)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScriptBatchResult{
+		BlockID:     block.ID,
+		BlockHeight: block.Height,
+		Values:      make([]cadence.Value, len(b.Scripts)),
+		Errors:      make([]error, len(b.Scripts)),
+	}
+
+	workers := b.NumberOfWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(b.Scripts) {
+		workers = len(b.Scripts)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result.Values[i], result.Errors[i] = b.Scripts[i].runAt(ctx, block)
+			}
+		}()
+	}
+
+	for i := range b.Scripts {
+		if ctx.Err() != nil {
+			result.Errors[i] = ctx.Err()
+			continue
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			result.Errors[i] = ctx.Err()
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return result, nil
+}
+
+// Err returns the errors of the failed scripts joined together, or nil if all scripts succeeded
+func (r *ScriptBatchResult) Err() error {
+	var errs []error
+	for i, err := range r.Errors {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("script %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
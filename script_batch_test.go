@@ -0,0 +1,71 @@
+package splash_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/cadence"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptBatch(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	double := g.Script(`
+access(all) fun main(value: Int): Int {
+  return value * 2
+}`)
+
+	t.Run("Return results in input order", func(t *testing.T) {
+		batch := g.ScriptBatch().Workers(4)
+		for i := 0; i < 50; i++ {
+			batch = batch.Add(double.Args(i))
+		}
+		batch = batch.Add(g.Script(`access(all) fun main(): Int { panic("boom") }`))
+
+		result, err := batch.Run(ctx)
+		require.NoError(t, err)
+		require.Len(t, result.Values, 51)
+
+		for i := 0; i < 50; i++ {
+			assert.NoError(t, result.Errors[i])
+			assert.Equal(t, cadence.NewInt(i*2), result.Values[i])
+		}
+		assert.ErrorContains(t, result.Errors[50], "boom")
+		assert.ErrorContains(t, result.Err(), "script 50:")
+	})
+
+	t.Run("Pin scripts to the same block", func(t *testing.T) {
+		height := g.Script(`
+access(all) fun main(): UInt64 {
+  return getCurrentBlock().height
+}`)
+		latest, err := height.RunWithResult(ctx)
+		require.NoError(t, err)
+
+		result, err := g.ScriptBatch(height, height.AtHeight(latest.BlockHeight+100)).AtHeight(latest.BlockHeight).Run(ctx)
+		require.NoError(t, err)
+		require.NoError(t, result.Err())
+		assert.Equal(t, latest.BlockHeight, result.BlockHeight)
+		assert.Equal(t, latest.Value, result.Values[0])
+		assert.Equal(t, latest.Value, result.Values[1])
+	})
+
+	t.Run("Stop on cancelled context", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		result, err := g.ScriptBatch(double.Args(1), double.Args(2)).Run(cancelled)
+		if err != nil {
+			assert.ErrorIs(t, err, context.Canceled)
+			return
+		}
+		for _, scriptErr := range result.Errors {
+			assert.ErrorIs(t, scriptErr, context.Canceled)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package splash
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+)
+
+// ScriptCache stores script results. Keys identify the script code, its arguments and the block height
+// the script was executed at, so a cached result never goes stale, but entries may be evicted at any time.
+type ScriptCache interface {
+	Get(key string) (cadence.Value, bool)
+	Set(key string, value cadence.Value)
+}
+
+// ScriptCacheStats counts the lookups in the script cache of a connector
+type ScriptCacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// WithScriptCache sets the cache used for the results of scripts. Pass nil to disable caching.
+func (c *Connector) WithScriptCache(cache ScriptCache) *Connector {
+	c.ScriptCache = cache
+	return c
+}
+
+// ScriptCacheStats returns the number of script cache hits and misses since the connector was created
+func (c *Connector) ScriptCacheStats() ScriptCacheStats {
+	return ScriptCacheStats{
+		Hits:   c.scriptCacheHits.Load(),
+		Misses: c.scriptCacheMisses.Load(),
+	}
+}
+
+// scriptCacheKey hashes the script code, its JSON-Cadence encoded arguments and the block height
+func scriptCacheKey(code []byte, args []cadence.Value, height uint64) (string, error) {
+	hash := sha256.New()
+	hash.Write(code)
+	for _, arg := range args {
+		encoded, err := jsoncdc.Encode(arg)
+		if err != nil {
+			return "", err
+		}
+		hash.Write([]byte{0})
+		hash.Write(encoded)
+	}
+	hash.Write([]byte{0})
+	hash.Write(binary.BigEndian.AppendUint64(nil, height))
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// LRUScriptCache is an in-memory ScriptCache that evicts the least recently used entries
+// once it's full, and entries older than its TTL
+type LRUScriptCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruScriptCacheEntry struct {
+	key     string
+	value   cadence.Value
+	expires time.Time
+}
+
+// NewLRUScriptCache creates an in-memory cache holding up to size results for at most ttl.
+// A size of 0 means no limit and a ttl of 0 means results don't expire.
+func NewLRUScriptCache(size int, ttl time.Duration) *LRUScriptCache {
+	return &LRUScriptCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the cached result for the key
+func (c *LRUScriptCache) Get(key string) (cadence.Value, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruScriptCacheEntry)
+	if c.ttl > 0 && time.Now().After(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set stores a result, evicting the least recently used one if the cache is full
+func (c *LRUScriptCache) Set(key string, value cadence.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := time.Now().Add(c.ttl)
+	if element, found := c.entries[key]; found {
+		entry := element.Value.(*lruScriptCacheEntry)
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruScriptCacheEntry{key: key, value: value, expires: expires})
+	if c.size > 0 && c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Len returns the number of cached results, including expired ones that haven't been evicted yet
+func (c *LRUScriptCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRUScriptCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruScriptCacheEntry).key)
+}
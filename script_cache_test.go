@@ -0,0 +1,88 @@
+package splash_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUScriptCache(t *testing.T) {
+	t.Run("Evict least recently used results", func(t *testing.T) {
+		cache := NewLRUScriptCache(2, 0)
+		cache.Set("a", cadence.NewInt(1))
+		cache.Set("b", cadence.NewInt(2))
+
+		_, found := cache.Get("a")
+		assert.True(t, found)
+
+		cache.Set("c", cadence.NewInt(3))
+		assert.Equal(t, 2, cache.Len())
+
+		_, found = cache.Get("b")
+		assert.False(t, found)
+		value, found := cache.Get("a")
+		assert.True(t, found)
+		assert.Equal(t, cadence.NewInt(1), value)
+	})
+
+	t.Run("Expire results", func(t *testing.T) {
+		cache := NewLRUScriptCache(0, 10*time.Millisecond)
+		cache.Set("a", cadence.NewInt(1))
+
+		time.Sleep(20 * time.Millisecond)
+		_, found := cache.Get("a")
+		assert.False(t, found)
+		assert.Equal(t, 0, cache.Len())
+	})
+}
+
+func TestScriptCache(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+	g.WithScriptCache(NewLRUScriptCache(100, time.Minute))
+
+	ctx := context.Background()
+	script := g.Script(`
+access(all) fun main(value: Int): [UInt64] {
+  return [UInt64(value), getCurrentBlock().height]
+}`)
+
+	first, err := script.Args(1).RunReturns(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ScriptCacheStats{Hits: 0, Misses: 1}, g.ScriptCacheStats())
+
+	second, err := script.Args(1).RunReturns(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, ScriptCacheStats{Hits: 1, Misses: 1}, g.ScriptCacheStats())
+
+	_, err = script.Args(2).RunReturns(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ScriptCacheStats{Hits: 1, Misses: 2}, g.ScriptCacheStats())
+
+	_, err = script.Args(1).NoCache().RunReturns(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ScriptCacheStats{Hits: 1, Misses: 2}, g.ScriptCacheStats())
+
+	pinned, err := script.Args(1).RunWithResult(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ScriptCacheStats{Hits: 2, Misses: 2}, g.ScriptCacheStats())
+
+	_, err = g.Transaction(`transaction {}`).SignProposeAndPayAsService().RunE(ctx)
+	require.NoError(t, err)
+
+	third, err := script.Args(1).RunReturns(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, third)
+	assert.Equal(t, ScriptCacheStats{Hits: 2, Misses: 3}, g.ScriptCacheStats())
+
+	old, err := script.Args(1).AtHeight(pinned.BlockHeight).RunReturns(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, old)
+	assert.Equal(t, ScriptCacheStats{Hits: 3, Misses: 3}, g.ScriptCacheStats())
+}
@@ -0,0 +1,132 @@
+package splash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/cadence"
+)
+
+// ScriptPage is a page of the array returned by a paged script
+type ScriptPage struct {
+	Offset      uint64
+	Values      []cadence.Value
+	BlockHeight uint64
+	// Err is set on the last page sent if the script failed
+	Err error
+}
+
+// RunPaged runs a script page by page and sends the pages on the returned channel, which is closed after the last page.
+//
+// The last two parameters of the script must be the offset and the limit of the page, e.g.
+// `main(account: Address, offset: UInt64, limit: UInt64): [UInt64]`. They are set automatically and must not be
+// passed as arguments. The script must return an array with at most limit elements. Paging stops at the first
+// page that has less than pageSize elements, when the script fails or when the context is cancelled.
+// All pages are read from the same block: the latest one, unless AtHeight or AtBlockID is set.
+func (t FlowScriptBuilder) RunPaged(ctx context.Context, pageSize uint64) <-chan ScriptPage {
+	pages := make(chan ScriptPage, 1)
+
+	go func() {
+		defer close(pages)
+
+		send := func(page ScriptPage) bool {
+			select {
+			case pages <- page:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		offsetParameter, limitParameter, err := t.pageParameters(pageSize)
+		if err != nil {
+			send(ScriptPage{Err: err})
+			return
+		}
+
+		block, err := t.block(ctx)
+		if err != nil {
+			send(ScriptPage{Err: err})
+			return
+		}
+
+		for offset := uint64(0); ; offset += pageSize {
+			offsetValue, err := ToCadenceValue(offset, offsetParameter.Type)
+			if err != nil {
+				send(ScriptPage{Offset: offset, BlockHeight: block.Height, Err: err})
+				return
+			}
+			limitValue, err := ToCadenceValue(pageSize, limitParameter.Type)
+			if err != nil {
+				send(ScriptPage{Offset: offset, BlockHeight: block.Height, Err: err})
+				return
+			}
+
+			value, err := t.
+				NamedArgument(offsetParameter.Name, offsetValue).
+				NamedArgument(limitParameter.Name, limitValue).
+				runAt(ctx, block)
+			if err != nil {
+				send(ScriptPage{Offset: offset, BlockHeight: block.Height, Err: err})
+				return
+			}
+
+			array, ok := value.(cadence.Array)
+			if !ok {
+				err = fmt.Errorf("a paged script must return an array, got %s", cadenceTypeName(value))
+				send(ScriptPage{Offset: offset, BlockHeight: block.Height, Err: err})
+				return
+			}
+
+			if !send(ScriptPage{Offset: offset, Values: array.Values, BlockHeight: block.Height}) {
+				return
+			}
+			if uint64(len(array.Values)) < pageSize {
+				return
+			}
+		}
+	}()
+
+	return pages
+}
+
+// RunAllPages runs a paged script like RunPaged and returns the elements of all pages
+func (t FlowScriptBuilder) RunAllPages(ctx context.Context, pageSize uint64) ([]cadence.Value, error) {
+	var values []cadence.Value
+	for page := range t.RunPaged(ctx, pageSize) {
+		if page.Err != nil {
+			return nil, page.Err
+		}
+		values = append(values, page.Values...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// pageParameters returns the offset and limit parameters of a paged script
+func (t FlowScriptBuilder) pageParameters(pageSize uint64) (parameter, parameter, error) {
+	if t.err != nil {
+		return parameter{}, parameter{}, t.err
+	}
+	if pageSize == 0 {
+		return parameter{}, parameter{}, errors.New("the page size must be positive")
+	}
+
+	code, err := t.code()
+	if err != nil {
+		return parameter{}, parameter{}, err
+	}
+	parameters, err := parseParameters(code)
+	if err != nil {
+		return parameter{}, parameter{}, err
+	}
+	if len(parameters) < 2 {
+		return parameter{}, parameter{}, errors.New("a paged script must end with offset and limit parameters")
+	}
+
+	return parameters[len(parameters)-2], parameters[len(parameters)-1], nil
+}
@@ -0,0 +1,77 @@
+package splash_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/cadence"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPaged(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	script := g.Script(`
+access(all) fun main(count: Int, offset: UInt64, limit: Int): [Int] {
+  let result: [Int] = []
+  var i = Int(offset)
+  while i < count && result.length < limit {
+    result.append(i)
+    i = i + 1
+  }
+  return result
+}`)
+
+	t.Run("Stream pages until a short page", func(t *testing.T) {
+		var sizes []int
+		var offsets []uint64
+		var heights []uint64
+		for page := range script.Args(25).RunPaged(ctx, 10) {
+			require.NoError(t, page.Err)
+			sizes = append(sizes, len(page.Values))
+			offsets = append(offsets, page.Offset)
+			heights = append(heights, page.BlockHeight)
+		}
+
+		assert.Equal(t, []int{10, 10, 5}, sizes)
+		assert.Equal(t, []uint64{0, 10, 20}, offsets)
+		assert.Equal(t, heights[0], heights[2])
+	})
+
+	t.Run("Collect all pages", func(t *testing.T) {
+		values, err := script.Args(20).RunAllPages(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, values, 20)
+		assert.Equal(t, cadence.NewInt(19), values[19])
+	})
+
+	t.Run("Stop when the context is cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		pages := script.Args(100).RunPaged(cancelled, 10)
+
+		page := <-pages
+		require.NoError(t, page.Err)
+		cancel()
+
+		for range pages {
+			// the channel is closed once the runner notices the cancellation
+		}
+		_, err := script.Args(100).RunAllPages(cancelled, 10)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Fail on scripts without paging parameters", func(t *testing.T) {
+		_, err := g.Script(`access(all) fun main(): [Int] { return [] }`).RunAllPages(ctx, 10)
+		assert.EqualError(t, err, "a paged script must end with offset and limit parameters")
+
+		_, err = g.Script(`
+access(all) fun main(offset: Int, limit: Int): Int {
+  return 0
+}`).RunAllPages(ctx, 10)
+		assert.EqualError(t, err, "a paged script must return an array, got Int")
+	})
+}
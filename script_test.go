@@ -100,3 +100,29 @@ func TestScriptArguments(t *testing.T) {
 		assert.Contains(t, builder.Arguments, cadence.NewInt256(256))
 	})
 }
+
+func TestScriptDeferredErrors(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("Unknown account argument", func(t *testing.T) {
+		builder := g.Script("access(all) fun main(a: Address): Address { return a }").AccountArgument("nobody")
+		assert.ErrorIs(t, builder.Err(), ErrUnknownAccount)
+
+		_, err := builder.RunReturns(ctx)
+		assert.ErrorIs(t, err, ErrUnknownAccount)
+	})
+
+	t.Run("Invalid Fix64 argument", func(t *testing.T) {
+		_, err := g.Script("access(all) fun main(a: Fix64): Fix64 { return a }").Fix64Argument("abc").RunReturns(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("RunFailOnError panics instead of exiting", func(t *testing.T) {
+		assert.Panics(t, func() {
+			g.Script("access(all) fun main(a: Address): Address { return a }").AccountArgument("nobody").RunFailOnError(ctx)
+		})
+	})
+}
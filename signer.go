@@ -0,0 +1,127 @@
+package splash
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flowkit/v2/accounts"
+)
+
+// Signer signs transactions on behalf of an account key. The message passed to Sign is the
+// domain tagged payload or envelope; the signer is responsible for hashing it with the
+// hash algorithm of the key.
+type Signer interface {
+	Address() flow.Address
+	KeyIndex() uint32
+	Sign(message []byte) ([]byte, error)
+}
+
+// ContextSigner is implemented by signers that can be cancelled, e.g. a RemoteSigner. Transactions
+// are signed with SignContext and the context they are sent with.
+type ContextSigner interface {
+	Signer
+	SignContext(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// keyIndexSigner is implemented by signers that need to know which key of the account they sign for,
+// so that they can sign for the key leased from a proposal key pool
+type keyIndexSigner interface {
+	ForKeyIndex(keyIndex uint32) Signer
+}
+
+// AccountSigner signs with the key of an account from flow.json
+type AccountSigner struct {
+	Account *accounts.Account
+}
+
+// NewAccountSigner returns a signer that uses the key of the given flow.json account
+func NewAccountSigner(account *accounts.Account) *AccountSigner {
+	return &AccountSigner{Account: account}
+}
+
+// Address returns the address of the account
+func (s *AccountSigner) Address() flow.Address {
+	return s.Account.Address
+}
+
+// KeyIndex returns the index of the account key
+func (s *AccountSigner) KeyIndex() uint32 {
+	return s.Account.Key.Index()
+}
+
+// Sign signs the message with the account key
+func (s *AccountSigner) Sign(message []byte) ([]byte, error) {
+	signer, err := s.Account.Key.Signer(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(message)
+}
+
+// InMemorySigner signs with a private key held in memory
+type InMemorySigner struct {
+	address  flow.Address
+	keyIndex uint32
+	signer   crypto.InMemorySigner
+}
+
+// NewInMemorySigner returns a signer for the given account key
+func NewInMemorySigner(address flow.Address, keyIndex uint32, privateKey crypto.PrivateKey, hashAlgo crypto.HashAlgorithm) (*InMemorySigner, error) {
+	// decoded ECDSA keys only derive their public part on demand, but recent Go versions need it for signing
+	_ = privateKey.PublicKey()
+
+	signer, err := crypto.NewInMemorySigner(privateKey, hashAlgo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InMemorySigner{
+		address:  address,
+		keyIndex: keyIndex,
+		signer:   signer,
+	}, nil
+}
+
+// Address returns the address of the account
+func (s *InMemorySigner) Address() flow.Address {
+	return s.address
+}
+
+// KeyIndex returns the index of the account key
+func (s *InMemorySigner) KeyIndex() uint32 {
+	return s.keyIndex
+}
+
+// Sign signs the message with the private key
+func (s *InMemorySigner) Sign(message []byte) ([]byte, error) {
+	return s.signer.Sign(message)
+}
+
+// sdkSigner adapts a Signer to the signer interface of the Flow SDK.
+// The SDK only uses it for signing, so the public key is not exposed.
+type sdkSigner struct {
+	Signer
+	ctx context.Context
+}
+
+func (s sdkSigner) Sign(message []byte) ([]byte, error) {
+	if signer, ok := s.Signer.(ContextSigner); ok {
+		return signer.SignContext(s.ctx, message)
+	}
+	return s.Signer.Sign(message)
+}
+
+func (s sdkSigner) PublicKey() crypto.PublicKey {
+	return nil
+}
+
+// SignerFor returns a signer for the flow.json account with the given name
+// (prefixed with the network name as usual)
+func (c *Connector) SignerFor(accountName string) (Signer, error) {
+	account, err := c.AccountE(accountName)
+	if err != nil {
+		return nil, err
+	}
+	return NewAccountSigner(account), nil
+}
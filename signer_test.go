@@ -0,0 +1,113 @@
+package splash_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateKey(t *testing.T) crypto.PrivateKey {
+	t.Helper()
+	privateKey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, []byte("a seed that is long enough for key generation"))
+	require.NoError(t, err)
+	return privateKey
+}
+
+func assertValidSignature(t *testing.T, privateKey crypto.PrivateKey, signature, message []byte) {
+	t.Helper()
+	valid, err := privateKey.PublicKey().Verify(signature, message, crypto.NewSHA3_256())
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	privateKey := generateKey(t)
+	address := flow.HexToAddress("0x01cf0e2f2f715450")
+	fileName := filepath.Join(t.TempDir(), "key.json")
+
+	require.NoError(t, WriteKeystoreFile(fileName, "correct horse", address, 2, privateKey, crypto.SHA3_256))
+
+	t.Run("Decrypt with the right passphrase", func(t *testing.T) {
+		signer, err := NewKeystoreSigner(fileName, "correct horse")
+		require.NoError(t, err)
+		assert.Equal(t, address, signer.Address())
+		assert.Equal(t, uint32(2), signer.KeyIndex())
+
+		message := []byte("hello")
+		signature, err := signer.Sign(message)
+		require.NoError(t, err)
+		assertValidSignature(t, privateKey, signature, message)
+	})
+
+	t.Run("Fail with a wrong passphrase", func(t *testing.T) {
+		_, err := NewKeystoreSigner(fileName, "battery staple")
+		assert.ErrorIs(t, err, ErrInvalidPassphrase)
+	})
+}
+
+func TestRemoteSigner(t *testing.T) {
+	privateKey := generateKey(t)
+	address := flow.HexToAddress("0x01cf0e2f2f715450")
+	keySigner, err := NewInMemorySigner(address, 0, privateKey, crypto.SHA3_256)
+	require.NoError(t, err)
+
+	var keyIndex uint32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		var req RemoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		keyIndex = req.KeyIndex
+		message, _ := hex.DecodeString(req.Message)
+		signature, _ := keySigner.Sign(message)
+		_ = json.NewEncoder(w).Encode(RemoteSignResponse{Signature: hex.EncodeToString(signature)})
+	}))
+	defer server.Close()
+
+	t.Run("Sign remotely", func(t *testing.T) {
+		signer := NewRemoteSigner(server.URL, address, 0).WithHeader("Authorization", "Bearer secret")
+
+		message := []byte("hello")
+		signature, err := signer.Sign(message)
+		require.NoError(t, err)
+		assertValidSignature(t, privateKey, signature, message)
+	})
+
+	t.Run("Sign for another key", func(t *testing.T) {
+		signer := NewRemoteSigner(server.URL, address, 0).WithHeader("Authorization", "Bearer secret")
+
+		_, err := signer.ForKeyIndex(3).Sign([]byte("hello"))
+		require.NoError(t, err)
+		assert.Equal(t, uint32(3), keyIndex)
+		assert.Equal(t, uint32(0), signer.KeyIndex())
+	})
+
+	t.Run("Give up when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := NewRemoteSigner(server.URL, address, 0).WithHeader("Authorization", "Bearer secret").SignContext(ctx, []byte("hello"))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Report errors from the remote signer", func(t *testing.T) {
+		_, err := NewRemoteSigner(server.URL, address, 0).Sign([]byte("hello"))
+		assert.ErrorContains(t, err, "403 Forbidden: forbidden")
+	})
+}
@@ -0,0 +1,213 @@
+package splash
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/parser"
+)
+
+// StructValue builds a Cadence struct of a type declared in a contract, e.g. "Debug.Foo", from a Go struct
+// or a map with string keys. The type ID is resolved from the address of the contract on the connector's
+// network, so the same code works on the emulator, testnet and mainnet.
+//
+// Fields are matched like DecodeCadence does: by the `cadence:"name"` tag or, without a tag, by the Go field
+// name (case-insensitive). Fields tagged `cadence:"-"` are skipped. Values are converted with ToCadenceValue
+// guided by the field types declared in the contract; fields of other struct types of the same contract are
+// built recursively.
+func (c *Connector) StructValue(typeName string, value any) (cadence.Struct, error) {
+	contractName, _, found := strings.Cut(typeName, ".")
+	if !found {
+		return cadence.Struct{}, fmt.Errorf("struct type %s must be qualified with its contract name", typeName)
+	}
+
+	address, err := c.ContractAddress(contractName)
+	if err != nil {
+		return cadence.Struct{}, err
+	}
+
+	contract, err := c.State.Contracts().ByName(contractName)
+	if err != nil {
+		return cadence.Struct{}, fmt.Errorf("%w: %w", ErrUnknownContract, err)
+	}
+	code, err := c.State.ReaderWriter().ReadFile(contract.Location)
+	if err != nil {
+		return cadence.Struct{}, fmt.Errorf("could not read contract file from path=%s", contract.Location)
+	}
+
+	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	if err != nil {
+		return cadence.Struct{}, fmt.Errorf("failed to parse contract %s: %w", contractName, err)
+	}
+
+	var contractDeclaration *ast.CompositeDeclaration
+	for _, declaration := range program.CompositeDeclarations() {
+		if declaration.Identifier.Identifier == contractName {
+			contractDeclaration = declaration
+			break
+		}
+	}
+	if contractDeclaration == nil {
+		return cadence.Struct{}, fmt.Errorf("contract %s is not declared in %s", contractName, contract.Location)
+	}
+
+	builder := structBuilder{
+		location: common.NewAddressLocation(nil, common.Address(address), contractName),
+		contract: contractDeclaration,
+	}
+
+	return builder.build(typeName, value)
+}
+
+// structBuilder builds struct values of the types declared in a contract
+type structBuilder struct {
+	location common.AddressLocation
+	contract *ast.CompositeDeclaration
+}
+
+// declaration finds a struct declared in the contract by its qualified identifier, e.g. "Debug.Foo"
+func (b structBuilder) declaration(qualifiedIdentifier string) *ast.CompositeDeclaration {
+	identifiers := strings.Split(qualifiedIdentifier, ".")
+	if identifiers[0] != b.contract.Identifier.Identifier {
+		return nil
+	}
+
+	declaration := b.contract
+	for _, identifier := range identifiers[1:] {
+		var nested *ast.CompositeDeclaration
+		for _, composite := range declaration.Members.Composites() {
+			if composite.Identifier.Identifier == identifier {
+				nested = composite
+				break
+			}
+		}
+		if nested == nil {
+			return nil
+		}
+		declaration = nested
+	}
+
+	if declaration == b.contract || declaration.Kind() != common.CompositeKindStructure {
+		return nil
+	}
+	return declaration
+}
+
+func (b structBuilder) build(qualifiedIdentifier string, value any) (cadence.Struct, error) {
+	declaration := b.declaration(qualifiedIdentifier)
+	if declaration == nil {
+		return cadence.Struct{}, fmt.Errorf("struct %s is not declared in contract %s", qualifiedIdentifier, b.location.Name)
+	}
+
+	goFields, err := goFieldValues(value)
+	if err != nil {
+		return cadence.Struct{}, fmt.Errorf("can't build %s: %w", qualifiedIdentifier, err)
+	}
+
+	fieldDeclarations := declaration.Members.Fields()
+	fields := make([]cadence.Field, len(fieldDeclarations))
+	values := make([]cadence.Value, len(fieldDeclarations))
+	for i, fieldDeclaration := range fieldDeclarations {
+		name := fieldDeclaration.Identifier.Identifier
+
+		goValue, found := goFields[strings.ToLower(name)]
+		if !found {
+			return cadence.Struct{}, fmt.Errorf("can't build %s: missing field %s", qualifiedIdentifier, name)
+		}
+
+		fieldValue, err := b.toCadenceValue(goValue, fieldDeclaration.TypeAnnotation.Type)
+		if err != nil {
+			return cadence.Struct{}, fmt.Errorf("can't build %s: field %s: %w", qualifiedIdentifier, name, err)
+		}
+
+		fieldType := fieldValue.Type()
+		if fieldType == nil {
+			fieldType = cadence.AnyStructType
+		}
+		fields[i] = cadence.Field{Identifier: name, Type: fieldType}
+		values[i] = fieldValue
+	}
+
+	structType := cadence.NewStructType(b.location, qualifiedIdentifier, fields, nil)
+	return cadence.NewStruct(values).WithType(structType), nil
+}
+
+// toCadenceValue converts a field value, building structs of the contract's own types
+func (b structBuilder) toCadenceValue(value any, cadenceType ast.Type) (cadence.Value, error) {
+	if _, ok := value.(cadence.Value); ok {
+		return ToCadenceValue(value, cadenceType)
+	}
+
+	switch t := cadenceType.(type) {
+	case *ast.OptionalType:
+		rv := reflect.ValueOf(value)
+		if value == nil || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+			return cadence.NewOptional(nil), nil
+		}
+		inner, err := b.toCadenceValue(value, t.Type)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptional(inner), nil
+	case *ast.NominalType:
+		qualifiedIdentifier := t.String()
+		if len(t.NestedIdentifiers) == 0 {
+			// types of the contract can be referenced without the contract name inside the contract
+			qualifiedIdentifier = b.location.Name + "." + qualifiedIdentifier
+		}
+		if b.declaration(qualifiedIdentifier) != nil {
+			return b.build(qualifiedIdentifier, value)
+		}
+	}
+
+	return ToCadenceValue(value, cadenceType)
+}
+
+// goFieldValues returns the fields of a Go struct or map, keyed by their lower case Cadence name
+func goFieldValues(value any) (map[string]any, error) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, errors.New("nil is not a valid struct")
+		}
+		rv = rv.Elem()
+	}
+
+	fields := map[string]any{}
+	switch rv.Kind() {
+	case reflect.Struct:
+		structType := rv.Type()
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, hasTag := field.Tag.Lookup("cadence")
+			if name == "-" {
+				continue
+			}
+			if !hasTag || name == "" {
+				name = field.Name
+			}
+			fields[strings.ToLower(name)] = rv.Field(i).Interface()
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("can't convert %s to a struct, map keys must be strings", rv.Type())
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			fields[strings.ToLower(iter.Key().String())] = iter.Value().Interface()
+		}
+	default:
+		return nil, fmt.Errorf("can't convert %s to a struct", rv.Type())
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,57 @@
+package splash_test
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence"
+	. "github.com/piprate/splash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type debugFoo struct {
+	Bar     string
+	Ignored string `cadence:"-"`
+}
+
+func TestStructValue(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	t.Run("Build struct from Go struct", func(t *testing.T) {
+		value, err := g.StructValue("Debug.Foo", debugFoo{Bar: "baz"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "A.f8d6e0586b0a20c7.Debug.Foo", value.Type().ID())
+		assert.Equal(t, cadence.String("baz"), cadence.SearchFieldByName(value, "bar"))
+	})
+
+	t.Run("Build struct from map", func(t *testing.T) {
+		tx := g.Transaction(`
+import Debug from "../contracts/Debug.cdc"
+
+transaction(value: Debug.Foo) {}`).StructArgument("Debug.Foo", map[string]any{"bar": "baz"})
+		require.NoError(t, tx.Err())
+		require.Len(t, tx.Arguments, 1)
+		assert.Equal(t, "A.f8d6e0586b0a20c7.Debug.Foo", tx.Arguments[0].Type().ID())
+	})
+
+	t.Run("Fail on missing fields", func(t *testing.T) {
+		_, err := g.StructValue("Debug.Foo", map[string]any{})
+		assert.EqualError(t, err, "can't build Debug.Foo: missing field bar")
+	})
+
+	t.Run("Fail on unknown types", func(t *testing.T) {
+		_, err := g.StructValue("Debug.Bar", debugFoo{})
+		assert.EqualError(t, err, "struct Debug.Bar is not declared in contract Debug")
+
+		_, err = g.StructValue("Unknown.Foo", debugFoo{})
+		assert.ErrorIs(t, err, ErrUnknownContract)
+	})
+
+	t.Run("Resolve contract addresses", func(t *testing.T) {
+		address, err := g.ContractAddress("FlowToken")
+		require.NoError(t, err)
+		assert.Equal(t, "0ae53cb6e3f42a79", address.Hex())
+	})
+}
@@ -4,9 +4,6 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
-
-	"path"
-	"strings"
 	"text/template"
 
 	"github.com/onflow/cadence/format"
@@ -68,23 +65,11 @@ func NewTemplateEngine(client *Connector, templateFS embed.FS, paths []string, r
 }
 
 func (e *TemplateEngine) loadContractAddresses(requiredWellKnownContracts []string) error {
-	contracts := e.client.State.Contracts()
-	network := e.client.Services.Network()
-	networkName := network.Name
-	deployedContracts, err := e.client.State.DeploymentContractsByNetwork(network)
+	addresses, err := e.client.ContractAddresses()
 	if err != nil {
 		return err
 	}
-	for _, contract := range *contracts {
-		for _, alias := range contract.Aliases {
-			if alias.Network == networkName {
-				e.wellKnownAddressesBinary[contract.Name] = alias.Address
-			}
-		}
-	}
-	for _, contract := range deployedContracts {
-		e.wellKnownAddressesBinary[strings.Split(path.Base(contract.Location()), ".")[0]] = contract.AccountAddress
-	}
+	e.wellKnownAddressesBinary = addresses
 
 	for _, requiredContractName := range requiredWellKnownContracts {
 		if _, found := e.wellKnownAddressesBinary[requiredContractName]; !found {
@@ -108,22 +93,54 @@ func (e *TemplateEngine) ContractAddress(contractName string) flow.Address {
 	return e.wellKnownAddressesBinary[contractName]
 }
 
+// GetStandardScript renders the template with the given ID and panics if it fails
 func (e *TemplateEngine) GetStandardScript(scriptID string) string {
+	s, err := e.GetStandardScriptE(scriptID)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// GetStandardScriptE renders the template with the given ID and returns ErrTemplateNotFound
+// if there is no such template.
+func (e *TemplateEngine) GetStandardScriptE(scriptID string) (string, error) {
 	s, found := e.preloadedTemplates[scriptID]
 	if !found {
+		if e.template.Lookup(scriptID) == nil {
+			return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, scriptID)
+		}
+
 		buf := &bytes.Buffer{}
 		if err := e.template.ExecuteTemplate(buf, scriptID, e.wellKnownAddresses); err != nil {
-			panic(err)
+			return "", err
 		}
 
 		s = buf.String()
 		e.preloadedTemplates[scriptID] = s
 	}
 
-	return s
+	return s, nil
 }
 
+// GetCustomScript renders the template with the given ID and parameters and panics if it fails
 func (e *TemplateEngine) GetCustomScript(scriptID string, params interface{}) string {
+	s, err := e.GetCustomScriptE(scriptID, params)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// GetCustomScriptE renders the template with the given ID and parameters and returns ErrTemplateNotFound
+// if there is no such template.
+func (e *TemplateEngine) GetCustomScriptE(scriptID string, params interface{}) (string, error) {
+	if e.template.Lookup(scriptID) == nil {
+		return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, scriptID)
+	}
+
 	data := map[string]interface{}{
 		ParamsKey: params,
 	}
@@ -132,22 +149,36 @@ func (e *TemplateEngine) GetCustomScript(scriptID string, params interface{}) st
 	}
 	buf := &bytes.Buffer{}
 	if err := e.template.ExecuteTemplate(buf, scriptID, data); err != nil {
-		panic(err)
+		return "", err
 	}
 
-	return buf.String()
+	return buf.String(), nil
 }
 
+// NewTransaction starts a transaction builder using the template with the given ID.
+// If the template can't be rendered, the error is returned when the transaction is run.
 func (e *TemplateEngine) NewTransaction(scriptID string) FlowTransactionBuilder {
-	return e.client.Transaction(e.GetStandardScript(scriptID))
+	code, err := e.GetStandardScriptE(scriptID)
+	tb := e.client.Transaction(code)
+	if err != nil {
+		tb.fail(err)
+	}
+	return tb
 }
 
 func (e *TemplateEngine) NewInlineTransaction(script string) FlowTransactionBuilder {
 	return e.client.Transaction(script)
 }
 
+// NewScript starts a script builder using the template with the given ID.
+// If the template can't be rendered, the error is returned when the script is run.
 func (e *TemplateEngine) NewScript(scriptID string) FlowScriptBuilder {
-	return e.client.Script(e.GetStandardScript(scriptID))
+	code, err := e.GetStandardScriptE(scriptID)
+	sb := e.client.Script(code)
+	if err != nil {
+		sb.fail(err)
+	}
+	return sb
 }
 
 func (e *TemplateEngine) NewInlineScript(script string) FlowScriptBuilder {
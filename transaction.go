@@ -4,15 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
 	"time"
 
-	"github.com/araddon/dateparse"
 	"github.com/onflow/cadence"
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flowkit/v2"
-	"github.com/onflow/flowkit/v2/accounts"
 	"github.com/onflow/flowkit/v2/transactions"
 )
 
@@ -22,7 +18,7 @@ func (c *Connector) TransactionFromFile(filename string) FlowTransactionBuilder
 		Connector:      c,
 		FileName:       filename,
 		Arguments:      []cadence.Value{},
-		PayloadSigners: []*accounts.Account{},
+		PayloadSigners: []Signer{},
 		GasLimit:       9999,
 	}
 }
@@ -34,7 +30,7 @@ func (c *Connector) Transaction(content string) FlowTransactionBuilder {
 		FileName:       "inline",
 		Content:        content,
 		Arguments:      []cadence.Value{},
-		PayloadSigners: []*accounts.Account{},
+		PayloadSigners: []Signer{},
 		GasLimit:       9999,
 	}
 }
@@ -45,44 +41,113 @@ func (tb FlowTransactionBuilder) Gas(limit uint64) FlowTransactionBuilder {
 	return tb
 }
 
+// Retry overrides the retry policy of the connector for this transaction
+func (tb FlowTransactionBuilder) Retry(policy RetryPolicy) FlowTransactionBuilder {
+	tb.retryPolicy = &policy
+	return tb
+}
+
+// NoRetry disables retries for this transaction
+func (tb FlowTransactionBuilder) NoRetry() FlowTransactionBuilder {
+	return tb.Retry(NoRetryPolicy())
+}
+
+// RetryPolicy returns the retry policy in effect for this transaction
+func (tb FlowTransactionBuilder) RetryPolicy() RetryPolicy {
+	if tb.retryPolicy != nil {
+		return *tb.retryPolicy
+	}
+	return tb.Connector.RetryPolicy
+}
+
+// Err returns the first error recorded while building the transaction, if any
+func (tb FlowTransactionBuilder) Err() error {
+	return tb.err
+}
+
+// fail records a deferred error that will be returned by RunE. Only the first error is kept.
+func (tb *FlowTransactionBuilder) fail(err error) {
+	if tb.err == nil {
+		tb.err = err
+	}
+}
+
 // ProposeAs sets the proposer
 func (tb FlowTransactionBuilder) ProposeAs(proposer string) FlowTransactionBuilder {
-	tb.Proposer = tb.Connector.Account(proposer)
+	signer, err := tb.Connector.SignerFor(proposer)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+	return tb.ProposeWith(signer)
+}
+
+// ProposeWith sets the proposer to a custom signer
+func (tb FlowTransactionBuilder) ProposeWith(signer Signer) FlowTransactionBuilder {
+	tb.Proposer = signer
 	return tb
 }
 
 // PayAs sets the payer
 func (tb FlowTransactionBuilder) PayAs(payer string) FlowTransactionBuilder {
-	tb.Payer = tb.Connector.Account(payer)
+	signer, err := tb.Connector.SignerFor(payer)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+	return tb.PayWith(signer)
+}
+
+// PayWith sets the payer to a custom signer
+func (tb FlowTransactionBuilder) PayWith(signer Signer) FlowTransactionBuilder {
+	tb.Payer = signer
 	return tb
 }
 
 // SignAndProposeAs set the proposer and envelope signer
 func (tb FlowTransactionBuilder) SignAndProposeAs(signer string) FlowTransactionBuilder {
-	tb.Proposer = tb.Connector.Account(signer)
-	tb.MainSigner = tb.Proposer
+	s, err := tb.Connector.SignerFor(signer)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+	return tb.SignAndProposeWith(s)
+}
+
+// SignAndProposeWith set the proposer and envelope signer to a custom signer
+func (tb FlowTransactionBuilder) SignAndProposeWith(signer Signer) FlowTransactionBuilder {
+	tb.Proposer = signer
+	tb.MainSigner = signer
 	return tb
 }
 
 // SignProposeAndPayAs set the payer, proposer and envelope signer
 func (tb FlowTransactionBuilder) SignProposeAndPayAs(signer string) FlowTransactionBuilder {
-	tb.Proposer = tb.Connector.Account(signer)
-	tb.Payer = tb.Proposer
-	tb.MainSigner = tb.Proposer
+	s, err := tb.Connector.SignerFor(signer)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+	return tb.SignProposeAndPayWith(s)
+}
+
+// SignProposeAndPayWith set the payer, proposer and envelope signer to a custom signer
+func (tb FlowTransactionBuilder) SignProposeAndPayWith(signer Signer) FlowTransactionBuilder {
+	tb.Proposer = signer
+	tb.Payer = signer
+	tb.MainSigner = signer
 	return tb
 }
 
 // SignProposeAndPayAsService set the payer, proposer and envelope signer
 func (tb FlowTransactionBuilder) SignProposeAndPayAsService() FlowTransactionBuilder {
 	key := fmt.Sprintf("%s-account", tb.Connector.Services.Network().Name)
-	account, err := tb.Connector.State.Accounts().ByName(key)
+	account, err := tb.Connector.accountByFullName(key)
 	if err != nil {
-		log.Fatal(err)
+		tb.fail(err)
+		return tb
 	}
-	tb.Proposer = account
-	tb.Payer = tb.Proposer
-	tb.MainSigner = tb.Proposer
-	return tb
+	return tb.SignProposeAndPayWith(NewAccountSigner(account))
 }
 
 // RawAccountArgument add an account from a string as an argument
@@ -94,9 +159,11 @@ func (tb FlowTransactionBuilder) RawAccountArgument(key string) FlowTransactionB
 
 // AccountArgument add an account as an argument
 func (tb FlowTransactionBuilder) AccountArgument(key string) FlowTransactionBuilder {
-	f := tb.Connector
-
-	account := f.Account(key)
+	account, err := tb.Connector.AccountE(key)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
 	return tb.Argument(cadence.BytesToAddress(account.Address.Bytes()))
 }
 
@@ -209,40 +276,68 @@ func (tb FlowTransactionBuilder) Word64Argument(value uint64) FlowTransactionBui
 func (tb FlowTransactionBuilder) Fix64Argument(value string) FlowTransactionBuilder {
 	amount, err := cadence.NewFix64(value)
 	if err != nil {
-		panic(err)
+		tb.fail(err)
+		return tb
 	}
 	return tb.Argument(amount)
 }
 
 // DateStringAsUnixTimestamp sends a dateString parsed in the timezone as a unix timezone ufix
 func (tb FlowTransactionBuilder) DateStringAsUnixTimestamp(dateString string, timezone string) FlowTransactionBuilder {
-	return tb.UFix64Argument(parseTime(dateString, timezone))
+	t, err := parseTimeInZone(dateString, timezone)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+	return tb.DateArgument(t)
 }
 
-func parseTime(timeString, location string) string {
-	loc, err := time.LoadLocation(location)
+// DateStringArgument sends a dateString parsed in the location as a unix timestamp ufix
+func (tb FlowTransactionBuilder) DateStringArgument(dateString string, location *time.Location) FlowTransactionBuilder {
+	t, err := ParseTimeInLocation(dateString, location)
 	if err != nil {
-		panic(err)
+		tb.fail(err)
+		return tb
 	}
+	return tb.DateArgument(t)
+}
 
-	time.Local = loc
-	t, err := dateparse.ParseLocal(timeString)
+// DateArgument sends a time as a unix timestamp ufix, keeping fractions of a second
+func (tb FlowTransactionBuilder) DateArgument(value time.Time) FlowTransactionBuilder {
+	timestamp, err := TimeToUFix64(value)
 	if err != nil {
-		panic(err)
+		tb.fail(err)
+		return tb
 	}
-
-	return fmt.Sprintf("%d.0", t.Unix())
+	return tb.Argument(timestamp)
 }
 
 // UFix64Argument add a UFix64 Argument to the transaction
 func (tb FlowTransactionBuilder) UFix64Argument(value string) FlowTransactionBuilder {
 	amount, err := cadence.NewUFix64(value)
 	if err != nil {
-		panic(err)
+		tb.fail(err)
+		return tb
 	}
 	return tb.Argument(amount)
 }
 
+// DecimalArgument add a UFix64 or Fix64 Argument to the transaction, depending on the sign type of the decimal
+func (tb FlowTransactionBuilder) DecimalArgument(value Decimal) FlowTransactionBuilder {
+	return tb.Argument(value.Cadence())
+}
+
+// StructArgument add a struct Argument of a contract type such as "Debug.Foo" to the transaction, built from a Go value.
+// See Connector.StructValue for how the fields are mapped.
+func (tb FlowTransactionBuilder) StructArgument(typeName string, value any) FlowTransactionBuilder {
+	structValue, err := tb.Connector.StructValue(typeName, value)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+	return tb.Argument(structValue)
+}
+
 // Argument add an argument to the transaction
 func (tb FlowTransactionBuilder) Argument(value cadence.Value) FlowTransactionBuilder {
 	tb.Arguments = append(tb.Arguments, value)
@@ -255,7 +350,8 @@ func (tb FlowTransactionBuilder) StringArrayArgument(value ...string) FlowTransa
 	for i, val := range value {
 		v, err := cadence.NewString(val)
 		if err != nil {
-			panic(err)
+			tb.fail(err)
+			return tb
 		}
 		array[i] = v
 	}
@@ -263,9 +359,49 @@ func (tb FlowTransactionBuilder) StringArrayArgument(value ...string) FlowTransa
 	return tb
 }
 
+// NamedArgument adds an argument for the parameter with the given name. Named arguments are
+// placed according to the parameter order of the transaction when it's built.
+func (tb FlowTransactionBuilder) NamedArgument(name string, value cadence.Value) FlowTransactionBuilder {
+	tb.namedArguments = append(tb.namedArguments, namedArgument{Name: name, Value: value})
+	return tb
+}
+
+// ArgumentsByName adds arguments by parameter name, see NamedArgument
+func (tb FlowTransactionBuilder) ArgumentsByName(arguments map[string]cadence.Value) FlowTransactionBuilder {
+	tb.namedArguments = append(tb.namedArguments, namedArguments(arguments)...)
+	return tb
+}
+
+// Args converts Go values to Cadence arguments, guided by the parameter types of the transaction.
+// See ToCadenceValue for the supported conversions.
+func (tb FlowTransactionBuilder) Args(values ...any) FlowTransactionBuilder {
+	code, err := tb.getContractCode(tb.codeFileName())
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+
+	args, err := convertArguments(code, len(tb.Arguments), values)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+	tb.Arguments = append(tb.Arguments, args...)
+	return tb
+}
+
 // PayloadSigner set a signer for the payload
 func (tb FlowTransactionBuilder) PayloadSigner(value string) FlowTransactionBuilder {
-	signer := tb.Connector.Account(value)
+	signer, err := tb.Connector.SignerFor(value)
+	if err != nil {
+		tb.fail(err)
+		return tb
+	}
+	return tb.PayloadSignerWith(signer)
+}
+
+// PayloadSignerWith set a custom signer for the payload
+func (tb FlowTransactionBuilder) PayloadSignerWith(signer Signer) FlowTransactionBuilder {
 	tb.PayloadSigners = append(tb.PayloadSigners, signer)
 	return tb
 }
@@ -280,97 +416,259 @@ func (tb FlowTransactionBuilder) RunPrintEvents(ctx context.Context, ignoreField
 	PrintEvents(tb.Run(ctx), ignoreFields)
 }
 
-// Run runs the transaction
+// Run runs the transaction and panics if it fails
 func (tb FlowTransactionBuilder) Run(ctx context.Context) []flow.Event {
 	events, err := tb.RunE(ctx)
 	if err != nil {
-		tb.Connector.Logger.Error(fmt.Sprintf("Error executing script: %s output %v", tb.FileName, err))
-		os.Exit(1)
+		tb.Connector.Logger.Error(fmt.Sprintf("Error executing transaction: %s output %v", tb.FileName, err))
+		panic(err)
 	}
 	return events
 }
 
 // RunE runs returns error
 func (tb FlowTransactionBuilder) RunE(ctx context.Context) ([]flow.Event, error) {
+	res, err := tb.RunWithResultE(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Events, nil
+}
+
+// RunWithResultE runs the transaction and returns its full result, including the transaction ID,
+// block, status, computation used and events. If the transaction was applied but failed,
+// both the result and the transaction error are returned.
+//
+// Transient errors are retried according to the retry policy. Once the transaction has been sent,
+// it's only sent again if it was rejected because of an outdated proposal key sequence number,
+// in which case it's rebuilt with a fresh sequence number.
+func (tb FlowTransactionBuilder) RunWithResultE(ctx context.Context) (*TransactionRunResult, error) {
+	policy := tb.RetryPolicy()
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsTransientError
+	}
+
+	sent := false
+	policy.Retryable = func(err error) bool {
+		if sent && !isSequenceNumberMismatch(err) {
+			// the transaction may have been applied, it's not safe to send it again
+			return false
+		}
+		return retryable(err)
+	}
+
+	var result *TransactionRunResult
+	err := tb.Connector.retry(ctx, policy, fmt.Sprintf("transaction %s", tb.FileName), func() error {
+		sent = false
+		handle, err := tb.send(ctx)
+		if err != nil {
+			result = nil
+			return err
+		}
+		sent = true
+
+		result, err = handle.Wait(ctx, flow.TransactionStatusSealed)
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+
+	tb.Connector.Logger.Debug(fmt.Sprintf("Transaction %s successfully applied", result.TransactionID))
+	return result, nil
+}
+
+// Send builds, signs and sends the transaction without waiting for it to be applied.
+// Use the returned handle to wait for the desired transaction status.
+// Building and sending is retried according to the retry policy.
+func (tb FlowTransactionBuilder) Send(ctx context.Context) (*TransactionHandle, error) {
+	var handle *TransactionHandle
+	err := tb.Connector.retry(ctx, tb.RetryPolicy(), fmt.Sprintf("sending transaction %s", tb.FileName), func() (err error) {
+		handle, err = tb.send(ctx)
+		return err
+	})
+	return handle, err
+}
+
+// send makes a single attempt to build, sign and send the transaction
+func (tb FlowTransactionBuilder) send(ctx context.Context) (*TransactionHandle, error) {
+	if err := tb.validate(); err != nil {
+		return nil, err
+	}
+
+	lease, err := tb.Connector.leaseProposalKey(ctx, tb.Proposer.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := tb.buildSigned(ctx, lease)
+	if err != nil {
+		lease.Release(false, nil)
+		return nil, err
+	}
+
+	sentTx, err := tb.Connector.Services.Gateway().SendSignedTransaction(ctx, tx)
+	if err != nil {
+		lease.Release(false, err)
+		return nil, err
+	}
+
+	handle := tb.Connector.TransactionHandle(sentTx.ID())
+	handle.RetryPolicy = tb.RetryPolicy()
+	if lease != nil {
+		handle.lease = lease
+		lease.watch()
+	}
+	return handle, nil
+}
 
+// validate returns the deferred builder error or an error if a required role is missing
+func (tb FlowTransactionBuilder) validate() error {
+	if tb.err != nil {
+		return tb.err
+	}
 	if tb.Proposer == nil {
-		return nil, errors.New("you need to set the proposer")
+		return ErrMissingProposer
 	}
 	if tb.Payer == nil {
-		return nil, errors.New("you need to set the payer")
+		return ErrMissingPayer
 	}
+	return nil
+}
 
-	codeFileName := fmt.Sprintf("./transactions/%s.cdc", tb.FileName)
-	code, err := tb.getContractCode(codeFileName)
+// BuildUnsigned builds the transaction without signing it, so that it can be exported
+// and signed by parties that are not available locally. The proposal key sequence number
+// is taken from the chain.
+func (tb FlowTransactionBuilder) BuildUnsigned(ctx context.Context) (*flow.Transaction, error) {
+	if err := tb.validate(); err != nil {
+		return nil, err
+	}
+
+	tx, _, err := tb.build(ctx, nil)
+	return tx, err
+}
+
+// buildSigned builds the transaction and collects all the required signatures.
+// If a proposal key lease is given, its key and sequence number are used for the proposer.
+func (tb FlowTransactionBuilder) buildSigned(ctx context.Context, lease *ProposalKeyLease) (*flow.Transaction, error) {
+	tx, signers, err := tb.build(ctx, lease)
 	if err != nil {
 		return nil, err
 	}
 
-	signerNames := map[string]bool{}
-	authorizerAccounts := tb.PayloadSigners
+	for _, signer := range signers {
+		keyIndex := signer.KeyIndex()
+		if lease != nil && signer.Address() == tb.Proposer.Address() {
+			keyIndex = lease.Index
+			if s, ok := signer.(keyIndexSigner); ok {
+				signer = s.ForKeyIndex(keyIndex)
+			}
+		}
+
+		if err := signTransaction(ctx, tx, signer, keyIndex); err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+// build builds the unsigned transaction and returns it together with the accounts
+// that need to sign it, in signing order (the payer is last)
+func (tb FlowTransactionBuilder) build(ctx context.Context, lease *ProposalKeyLease) (*flow.Transaction, []Signer, error) {
+
+	codeFileName := tb.codeFileName()
+	code, err := tb.getContractCode(codeFileName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args, err := resolveArguments(code, tb.Arguments, tb.namedArguments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateArguments(code, args); err != nil {
+		return nil, nil, err
+	}
+
+	signerAddresses := map[flow.Address]bool{}
+	authorizerSigners := tb.PayloadSigners
 	if tb.MainSigner != nil {
-		authorizerAccounts = append(authorizerAccounts, tb.MainSigner)
+		authorizerSigners = append(authorizerSigners, tb.MainSigner)
 	}
 
-	authorizers := make([]flow.Address, len(authorizerAccounts))
-	var signers []*accounts.Account
-	for i, signer := range authorizerAccounts {
-		authorizers[i] = signer.Address
-		if signer.Name != tb.Payer.Name {
+	authorizers := make([]flow.Address, len(authorizerSigners))
+	var signers []Signer
+	for i, signer := range authorizerSigners {
+		authorizers[i] = signer.Address()
+		if signer.Address() != tb.Payer.Address() {
 			signers = append(signers, signer)
 		}
-		signerNames[signer.Name] = true
+		signerAddresses[signer.Address()] = true
 	}
 
-	if _, found := signerNames[tb.Proposer.Name]; !found && tb.Proposer.Name != tb.Payer.Name {
-		return nil, errors.New("proposer doesn't match any authorizers or the payer")
+	if !signerAddresses[tb.Proposer.Address()] && tb.Proposer.Address() != tb.Payer.Address() {
+		return nil, nil, errors.New("proposer doesn't match any authorizers or the payer")
 	}
 
 	// we append the Payer at the end here so that it signs last
 	signers = append(signers, tb.Payer)
 
-	tx, err := tb.Connector.Services.BuildTransaction(
+	proposerKeyIndex := tb.Proposer.KeyIndex()
+	if lease != nil {
+		proposerKeyIndex = lease.Index
+	}
+
+	builtTx, err := tb.Connector.Services.BuildTransaction(
 		ctx,
 		transactions.AddressesRoles{
-			Proposer:    tb.Proposer.Address,
+			Proposer:    tb.Proposer.Address(),
 			Authorizers: authorizers,
-			Payer:       tb.Payer.Address,
+			Payer:       tb.Payer.Address(),
 		},
-		tb.Proposer.Key.Index(),
+		proposerKeyIndex,
 		flowkit.Script{
 			Code:     code,
-			Args:     tb.Arguments,
+			Args:     args,
 			Location: codeFileName,
 		},
 		tb.GasLimit,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	for _, signer := range signers {
-		err = tx.SetSigner(signer)
-		if err != nil {
-			return nil, err
-		}
-
-		tx, err = tx.Sign()
-		if err != nil {
-			return nil, err
-		}
+	tx := builtTx.FlowTransaction()
+	if lease != nil {
+		// use the locally tracked sequence number, as the one on chain
+		// doesn't account for transactions that are still in flight
+		tx.SetProposalKey(tb.Proposer.Address(), lease.Index, lease.SequenceNumber)
 	}
 
-	_, res, err := tb.Connector.Services.SendSignedTransaction(ctx, tx)
+	return tx, signers, nil
+}
+
+// signTransaction adds the signature of the signer to the transaction. The payer signs
+// the envelope, everyone else signs the payload.
+func signTransaction(ctx context.Context, tx *flow.Transaction, signer Signer, keyIndex uint32) error {
+	var err error
+	if signer.Address() == tx.Payer {
+		err = tx.SignEnvelope(signer.Address(), keyIndex, sdkSigner{signer, ctx})
+	} else {
+		err = tx.SignPayload(signer.Address(), keyIndex, sdkSigner{signer, ctx})
+	}
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	if res.Error != nil {
-		return nil, res.Error
-	}
+	return nil
+}
 
-	tb.Connector.Logger.Debug(fmt.Sprintf("Transaction %s successfully applied", tx.FlowTransaction().ID()))
-	return res.Events, nil
+func (tb FlowTransactionBuilder) codeFileName() string {
+	return fmt.Sprintf("./transactions/%s.cdc", tb.FileName)
 }
 
 func (tb FlowTransactionBuilder) getContractCode(codeFileName string) ([]byte, error) {
@@ -385,15 +683,20 @@ func (tb FlowTransactionBuilder) getContractCode(codeFileName string) ([]byte, e
 	return code, nil
 }
 
-// FlowTransactionBuilder used to create a builder pattern for a transaction
+// FlowTransactionBuilder used to create a builder pattern for a transaction.
+// Accounts set directly as the proposer, payer or signers are wrapped with NewAccountSigner.
 type FlowTransactionBuilder struct {
 	Connector      *Connector
 	FileName       string
 	Content        string
 	Arguments      []cadence.Value
-	Proposer       *accounts.Account
-	Payer          *accounts.Account
-	MainSigner     *accounts.Account
-	PayloadSigners []*accounts.Account
+	Proposer       Signer
+	Payer          Signer
+	MainSigner     Signer
+	PayloadSigners []Signer
 	GasLimit       uint64
+
+	namedArguments []namedArgument
+	retryPolicy    *RetryPolicy
+	err            error
 }
@@ -0,0 +1,93 @@
+package splash
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flowkit/v2"
+)
+
+// defaultStatusPollInterval is how often the transaction status is checked while waiting
+const defaultStatusPollInterval = time.Second
+
+// TransactionHandle refers to a transaction that has been sent to the network
+// but may not have reached the desired status yet
+type TransactionHandle struct {
+	ID           flow.Identifier
+	PollInterval time.Duration
+	// RetryPolicy is applied to status queries that fail while waiting
+	RetryPolicy RetryPolicy
+	connector   *Connector
+	lease       *ProposalKeyLease
+}
+
+// TransactionHandle returns a handle for an already sent transaction with the given ID
+func (c *Connector) TransactionHandle(id flow.Identifier) *TransactionHandle {
+	return &TransactionHandle{
+		ID:           id,
+		PollInterval: defaultStatusPollInterval,
+		RetryPolicy:  c.RetryPolicy,
+		connector:    c,
+	}
+}
+
+// Status fetches the current result of the transaction without waiting
+func (h *TransactionHandle) Status(ctx context.Context) (*TransactionRunResult, error) {
+	res, err := h.connector.Services.Gateway().GetTransactionResult(ctx, h.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// events are only present once the transaction is executed, so the block is fetched once per result
+	var blockTime time.Time
+	if len(res.Events) > 0 {
+		block, err := h.connector.Services.GetBlock(ctx, flowkit.BlockQuery{ID: &res.BlockID})
+		if err != nil {
+			return nil, err
+		}
+		blockTime = block.Timestamp
+	}
+
+	return newTransactionRunResult(h.ID, res, blockTime), nil
+}
+
+// Wait blocks until the transaction reaches the given status (Pending, Finalized, Executed or Sealed)
+// or the context is cancelled. If the transaction was executed with an error, both the result
+// and the transaction error are returned.
+func (h *TransactionHandle) Wait(ctx context.Context, status flow.TransactionStatus) (*TransactionRunResult, error) {
+	if status == flow.TransactionStatusUnknown || status == flow.TransactionStatusExpired {
+		return nil, fmt.Errorf("can't wait for transaction status %s", status)
+	}
+
+	for {
+		var result *TransactionRunResult
+		err := h.connector.retry(ctx, h.RetryPolicy, "transaction status query", func() (err error) {
+			result, err = h.Status(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		h.lease.settle(result)
+
+		if result.Status == flow.TransactionStatusExpired {
+			return result, fmt.Errorf("%w: %s", ErrTransactionExpired, h.ID)
+		}
+
+		if result.Status >= status {
+			if result.Error != nil {
+				return result, result.Error
+			}
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(h.PollInterval):
+		}
+	}
+}
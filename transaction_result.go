@@ -0,0 +1,66 @@
+package splash
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// TransactionRunResult holds everything known about a transaction once it has been applied
+type TransactionRunResult struct {
+	TransactionID    flow.Identifier
+	BlockID          flow.Identifier
+	BlockHeight      uint64
+	Status           flow.TransactionStatus
+	ComputationUsage uint64
+	// ErrorCode is the Cadence/FVM error code of a failed transaction, or 0 if it succeeded
+	ErrorCode int
+	Error     error
+	Events    []flow.Event
+	// FormatedEvents are the events with the timestamp of the block that includes the transaction
+	FormatedEvents []*FormatedEvent
+}
+
+var errorCodeRegexp = regexp.MustCompile(`\[Error Code: (\d+)\]`)
+
+// Succeeded returns true if the transaction was applied without errors
+func (r *TransactionRunResult) Succeeded() bool {
+	return r.Error == nil
+}
+
+func newTransactionRunResult(txID flow.Identifier, res *flow.TransactionResult, blockTime time.Time) *TransactionRunResult {
+	result := &TransactionRunResult{
+		TransactionID:    txID,
+		BlockID:          res.BlockID,
+		BlockHeight:      res.BlockHeight,
+		Status:           res.Status,
+		ComputationUsage: res.ComputationUsage,
+		Error:            res.Error,
+		Events:           res.Events,
+		FormatedEvents:   make([]*FormatedEvent, len(res.Events)),
+	}
+
+	if res.Error != nil {
+		result.ErrorCode = extractErrorCode(res.Error)
+	}
+
+	for i, event := range res.Events {
+		result.FormatedEvents[i] = ParseEvent(event, res.BlockHeight, blockTime, []string{})
+		result.FormatedEvents[i].BlockID = res.BlockID
+	}
+
+	return result
+}
+
+// extractErrorCode finds the first error code mentioned in the error message
+func extractErrorCode(err error) int {
+	match := errorCodeRegexp.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+
+	code, _ := strconv.Atoi(match[1])
+	return code
+}
@@ -0,0 +1,192 @@
+package splash
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+type (
+	// TransactionDescription is a summary of a transaction that signers can review before signing it
+	TransactionDescription struct {
+		ID                 string                 `json:"id"`
+		ReferenceBlockID   string                 `json:"referenceBlockId"`
+		GasLimit           uint64                 `json:"gasLimit"`
+		Proposer           ProposalKeyDescription `json:"proposer"`
+		Payer              string                 `json:"payer"`
+		Authorizers        []string               `json:"authorizers"`
+		Arguments          []json.RawMessage      `json:"arguments"`
+		CodeHash           string                 `json:"codeHash"`
+		PayloadSignatures  []SignatureDescription `json:"payloadSignatures"`
+		EnvelopeSignatures []SignatureDescription `json:"envelopeSignatures"`
+	}
+
+	// ProposalKeyDescription describes the proposal key of a transaction
+	ProposalKeyDescription struct {
+		Address        string `json:"address"`
+		KeyIndex       uint32 `json:"keyIndex"`
+		SequenceNumber uint64 `json:"sequenceNumber"`
+	}
+
+	// SignatureDescription describes a signature already attached to a transaction
+	SignatureDescription struct {
+		Address  string `json:"address"`
+		KeyIndex uint32 `json:"keyIndex"`
+	}
+)
+
+// DescribeTransaction returns the roles, JSON-Cadence arguments and SHA3-256 hash of the code of the transaction
+func DescribeTransaction(tx *flow.Transaction) TransactionDescription {
+	description := TransactionDescription{
+		ID:               tx.ID().String(),
+		ReferenceBlockID: tx.ReferenceBlockID.String(),
+		GasLimit:         tx.GasLimit,
+		Proposer: ProposalKeyDescription{
+			Address:        tx.ProposalKey.Address.HexWithPrefix(),
+			KeyIndex:       tx.ProposalKey.KeyIndex,
+			SequenceNumber: tx.ProposalKey.SequenceNumber,
+		},
+		Payer:              tx.Payer.HexWithPrefix(),
+		Authorizers:        make([]string, len(tx.Authorizers)),
+		Arguments:          make([]json.RawMessage, len(tx.Arguments)),
+		CodeHash:           hex.EncodeToString(crypto.NewSHA3_256().ComputeHash(tx.Script)),
+		PayloadSignatures:  describeSignatures(tx.PayloadSignatures),
+		EnvelopeSignatures: describeSignatures(tx.EnvelopeSignatures),
+	}
+
+	for i, authorizer := range tx.Authorizers {
+		description.Authorizers[i] = authorizer.HexWithPrefix()
+	}
+	for i, argument := range tx.Arguments {
+		description.Arguments[i] = bytes.TrimSpace(argument)
+	}
+
+	return description
+}
+
+func describeSignatures(signatures []flow.TransactionSignature) []SignatureDescription {
+	result := make([]SignatureDescription, len(signatures))
+	for i, signature := range signatures {
+		result[i] = SignatureDescription{
+			Address:  signature.Address.HexWithPrefix(),
+			KeyIndex: signature.KeyIndex,
+		}
+	}
+	return result
+}
+
+// EncodeTransaction encodes the transaction as RLP hex, the format used by the flow CLI
+func EncodeTransaction(tx *flow.Transaction) string {
+	return hex.EncodeToString(tx.Encode())
+}
+
+// DecodeTransaction decodes a transaction encoded as RLP hex
+func DecodeTransaction(encoded string) (*flow.Transaction, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(encoded), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction hex: %w", err)
+	}
+
+	return flow.DecodeTransaction(data)
+}
+
+// WriteTransactionFile writes the transaction to a file as RLP hex
+func WriteTransactionFile(fileName string, tx *flow.Transaction) error {
+	return os.WriteFile(fileName, []byte(EncodeTransaction(tx)), 0o600)
+}
+
+// ReadTransactionFile reads a transaction written as RLP hex, e.g. by WriteTransactionFile or the flow CLI
+func ReadTransactionFile(fileName string) (*flow.Transaction, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeTransaction(string(data))
+}
+
+// SignTransaction signs the transaction with the key of the given account from flow.json.
+// The payer signs the envelope, so it has to sign after all the other signers.
+func (c *Connector) SignTransaction(tx *flow.Transaction, accountName string) error {
+	signer, err := c.SignerFor(accountName)
+	if err != nil {
+		return err
+	}
+
+	return SignTransactionWith(tx, signer)
+}
+
+// SignTransactionWith signs the transaction with a custom signer.
+// The payer signs the envelope, so it has to sign after all the other signers.
+func SignTransactionWith(tx *flow.Transaction, signer Signer) error {
+	return signTransaction(context.Background(), tx, signer, signer.KeyIndex())
+}
+
+// AddSignaturesFromFile copies the signatures from a partially signed transaction file into the transaction.
+// Both transactions must have the same payload.
+func AddSignaturesFromFile(tx *flow.Transaction, fileName string) error {
+	partial, err := ReadTransactionFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	return AddSignatures(tx, partial)
+}
+
+// AddSignatures copies the signatures from a partially signed transaction into the transaction.
+// Both transactions must have the same payload. Signatures that are already present are skipped.
+func AddSignatures(tx, partial *flow.Transaction) error {
+	if !bytes.Equal(tx.PayloadMessage(), partial.PayloadMessage()) {
+		return errors.New("the partially signed transaction has a different payload")
+	}
+
+	for _, signature := range partial.PayloadSignatures {
+		if hasSignature(tx.PayloadSignatures, signature) {
+			continue
+		}
+		if len(tx.EnvelopeSignatures) > 0 {
+			return errors.New("payload signatures can't be added after the envelope has been signed")
+		}
+		tx.AddPayloadSignature(signature.Address, signature.KeyIndex, signature.Signature)
+	}
+
+	for _, signature := range partial.EnvelopeSignatures {
+		if !hasSignature(tx.EnvelopeSignatures, signature) {
+			tx.AddEnvelopeSignature(signature.Address, signature.KeyIndex, signature.Signature)
+		}
+	}
+
+	return nil
+}
+
+func hasSignature(signatures []flow.TransactionSignature, signature flow.TransactionSignature) bool {
+	for _, existing := range signatures {
+		if existing.Address == signature.Address && existing.KeyIndex == signature.KeyIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// SubmitSigned sends a fully signed transaction without waiting for it to be applied.
+// Use the returned handle to wait for the desired transaction status.
+func (c *Connector) SubmitSigned(ctx context.Context, tx *flow.Transaction) (*TransactionHandle, error) {
+	var sentTx *flow.Transaction
+	err := c.retry(ctx, c.RetryPolicy, "sending signed transaction", func() (err error) {
+		sentTx, err = c.Services.Gateway().SendSignedTransaction(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.TransactionHandle(sentTx.ID()), nil
+}
@@ -1,6 +1,7 @@
 package splash_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/onflow/cadence"
@@ -100,3 +101,45 @@ func TestTransactionArguments(t *testing.T) {
 		assert.Contains(t, builder.Arguments, cadence.NewInt256(256))
 	})
 }
+
+func TestTransactionDeferredErrors(t *testing.T) {
+	g, err := NewInMemoryTestConnector("examples", false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("Unknown proposer", func(t *testing.T) {
+		builder := g.Transaction("transaction {}").ProposeAs("nobody").PayAs("first")
+		assert.ErrorIs(t, builder.Err(), ErrUnknownAccount)
+
+		_, err := builder.RunE(ctx)
+		assert.ErrorIs(t, err, ErrUnknownAccount)
+		assert.Contains(t, err.Error(), "emulator-nobody")
+	})
+
+	t.Run("Unknown account argument", func(t *testing.T) {
+		_, err := g.Transaction("transaction {}").SignProposeAndPayAs("first").AccountArgument("nobody").RunE(ctx)
+		assert.ErrorIs(t, err, ErrUnknownAccount)
+	})
+
+	t.Run("Invalid UFix64 argument", func(t *testing.T) {
+		_, err := g.Transaction("transaction {}").SignProposeAndPayAs("first").UFix64Argument("abc").RunE(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing proposer", func(t *testing.T) {
+		_, err := g.Transaction("transaction {}").PayAs("first").RunE(ctx)
+		assert.ErrorIs(t, err, ErrMissingProposer)
+	})
+
+	t.Run("Missing payer", func(t *testing.T) {
+		_, err := g.Transaction("transaction {}").ProposeAs("first").RunE(ctx)
+		assert.ErrorIs(t, err, ErrMissingPayer)
+	})
+
+	t.Run("Run panics instead of exiting", func(t *testing.T) {
+		assert.Panics(t, func() {
+			g.Transaction("transaction {}").ProposeAs("nobody").Run(ctx)
+		})
+	})
+}